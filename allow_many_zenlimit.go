@@ -0,0 +1,130 @@
+package zenlimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var allowMany = redis.NewScript(`
+redis.replicate_commands()
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local n = tonumber(ARGV[1])
+local new_tats = {}
+local remainings = {}
+local retry_afters = {}
+local reset_afters = {}
+local allowed = true
+
+for i = 0, n - 1 do
+  local base = 2 + i * 4
+  local burst = tonumber(ARGV[base])
+  local rate = tonumber(ARGV[base + 1])
+  local period = tonumber(ARGV[base + 2])
+  local cost = tonumber(ARGV[base + 3])
+
+  local emission_interval = period / rate
+  local increment = emission_interval * cost
+  local burst_offset = emission_interval * burst
+
+  local tat = redis.call("GET", KEYS[i + 1])
+  if not tat then
+    tat = now
+  else
+    tat = tonumber(tat)
+  end
+  tat = math.max(tat, now)
+
+  local new_tat = tat + increment
+  local diff = now - (new_tat - burst_offset)
+  local remaining = diff / emission_interval
+
+  new_tats[i + 1] = new_tat
+  remainings[i + 1] = remaining
+
+  if remaining < 0 then
+    allowed = false
+    retry_afters[i + 1] = diff * -1
+    reset_afters[i + 1] = tat - now
+  else
+    retry_afters[i + 1] = -1
+    reset_afters[i + 1] = new_tat - now
+  end
+end
+
+local out = {}
+for i = 0, n - 1 do
+  local idx = i + 1
+  local cost = tonumber(ARGV[2 + i * 4 + 3])
+
+  if allowed and reset_afters[idx] > 0 then
+    redis.call("SET", KEYS[idx], new_tats[idx], "EX", math.ceil(reset_afters[idx]))
+  end
+
+  if remainings[idx] < 0 then
+    out[idx] = {0, 0, tostring(retry_afters[idx]), tostring(reset_afters[idx])}
+  elseif not allowed then
+    out[idx] = {0, remainings[idx], tostring(retry_afters[idx]), tostring(reset_afters[idx])}
+  else
+    out[idx] = {cost, remainings[idx], tostring(retry_afters[idx]), tostring(reset_afters[idx])}
+  end
+end
+return out
+`)
+
+// AllowMany evaluates several limits sharing a single key (e.g. per-second,
+// per-minute and per-hour tiers) in one Redis pipeline. A request is only
+// counted against every limit if all of them currently allow it.
+func (l *Limiter) AllowMany(ctx context.Context, key string, limits []Limit) ([]*Result, error) {
+	return l.AllowManyN(ctx, key, limits, 1)
+}
+
+func (l *Limiter) AllowManyN(ctx context.Context, key string, limits []Limit, n int) ([]*Result, error) {
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(limits))
+	values := make([]interface{}, 0, 1+len(limits)*4)
+	values = append(values, len(limits))
+	for i, limit := range limits {
+		keys[i] = keyWithPrefix(l.Options.KeyPrefix, fmt.Sprintf("%s:%d", key, i))
+		values = append(values, limit.Burst, limit.Rate, limit.Period.Seconds(), n)
+	}
+
+	v, err := allowMany.Run(ctx, l.client, keys, values...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := v.([]interface{})
+	results := make([]*Result, len(limits))
+	for i, row := range rows {
+		fields := row.([]interface{})
+
+		retryAfter, err := strconv.ParseFloat(fields[2].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+
+		resetAfter, err := strconv.ParseFloat(fields[3].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+
+		results[i] = &Result{
+			Limit:      limits[i],
+			Allowed:    int(fields[0].(int64)),
+			Remaining:  int(fields[1].(int64)),
+			RetryAfter: dur(retryAfter),
+			ResetAfter: dur(resetAfter),
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,35 @@
+package zenlimit
+
+import "context"
+
+// AllowMulti atomically checks n tokens against several tiered limits
+// sharing key (e.g. 10/s, 100/m, 1000/h) and returns the most restrictive
+// Result alongside every tier's individual Result, so callers combining
+// tiers don't need N racy sequential AllowN calls.
+func (l *Limiter) AllowMulti(ctx context.Context, key string, limits []Limit, n int) (*Result, []*Result, error) {
+	breakdown, err := l.AllowManyN(ctx, key, limits, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mostRestrictive(breakdown), breakdown, nil
+}
+
+// mostRestrictive returns the tier that would deny the request first:
+// denied beats allowed, and among denied tiers the one with the largest
+// RetryAfter wins. A tier that reports Allowed 0 only because another tier
+// denied keeps its own Remaining and has a RetryAfter of -1, so it never
+// outranks a tier that denied on its own. Results is assumed non-empty.
+func mostRestrictive(results []*Result) *Result {
+	most := results[0]
+	for _, r := range results[1:] {
+		switch {
+		case most.Allowed > 0 && r.Allowed == 0:
+			most = r
+		case most.Allowed == 0 && r.Allowed == 0 && r.RetryAfter > most.RetryAfter:
+			most = r
+		case most.Allowed > 0 && r.Allowed > 0 && r.Remaining < most.Remaining:
+			most = r
+		}
+	}
+	return most
+}
@@ -138,6 +138,167 @@ func (b *Backend) AllowAtMost(ctx context.Context, key string, limit zenlimiter.
 	}, nil
 }
 
+func (b *Backend) AllowMany(ctx context.Context, key string, limits []zenlimiter.Limit) ([]*zenlimiter.Result, error) {
+	return b.AllowManyN(ctx, key, limits, 1)
+}
+
+// AllowManyN evaluates every limit in limits against the shared key and only
+// commits any bucket state if all of them currently allow n requests. Because
+// the EchoVault client has no Lua-style scripting hook, the check-then-commit
+// is done in two passes: the first pass computes every tier's outcome
+// without writing, and only if every tier allows the request does the second
+// pass persist the new TAT values.
+func (b *Backend) AllowManyN(
+	ctx context.Context,
+	key string,
+	limits []zenlimiter.Limit,
+	n int,
+) ([]*zenlimiter.Result, error) {
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	now := float64(time.Now().UnixNano()) / 1e9
+	newTats := make([]float64, len(limits))
+	remainings := make([]float64, len(limits))
+	retryAfters := make([]float64, len(limits))
+	resetAfters := make([]float64, len(limits))
+	allowed := true
+
+	for i, limit := range limits {
+		rateLimitKey := fmt.Sprintf("%s%s:%d", keyPrefix, key, i)
+		emissionInterval := limit.Period.Seconds() / float64(limit.Rate)
+		burstOffset := emissionInterval * float64(limit.Burst)
+		increment := emissionInterval * float64(n)
+
+		tat, err := b.getTat(ctx, rateLimitKey, now)
+		if err != nil {
+			return nil, err
+		}
+
+		tat = math.Max(tat, now)
+		newTat := tat + increment
+		diff := now - (newTat - burstOffset)
+		remaining := diff / emissionInterval
+
+		newTats[i] = newTat
+		remainings[i] = remaining
+
+		if remaining < 0 {
+			allowed = false
+			retryAfters[i] = -diff
+			resetAfters[i] = tat - now
+		} else {
+			retryAfters[i] = -1
+			resetAfters[i] = newTat - now
+		}
+	}
+
+	results := make([]*zenlimiter.Result, len(limits))
+	for i, limit := range limits {
+		if allowed && resetAfters[i] > 0 {
+			rateLimitKey := fmt.Sprintf("%s%s:%d", keyPrefix, key, i)
+			_, _, err := b.client.Set(rateLimitKey, fmt.Sprintf("%f", newTats[i]), echovault.SetOptions{
+				EX: int((time.Duration(math.Ceil(resetAfters[i])) * time.Second).Seconds()),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// remainings[i] < 0 means this tier individually would have denied
+		// the request; every other tier still reports its true Remaining
+		// even when the overall request was denied by a different tier, so
+		// callers can tell which tier was the actual bottleneck. Allowed is
+		// always 0 when allowed is false, regardless of this tier's own
+		// headroom, since nothing was actually committed for any tier.
+		if remainings[i] < 0 {
+			results[i] = &zenlimiter.Result{
+				Limit:      limit,
+				Allowed:    0,
+				Remaining:  0,
+				RetryAfter: dur(retryAfters[i]),
+				ResetAfter: dur(resetAfters[i]),
+			}
+			continue
+		}
+
+		tierAllowed := n
+		if !allowed {
+			tierAllowed = 0
+		}
+		results[i] = &zenlimiter.Result{
+			Limit:      limit,
+			Allowed:    tierAllowed,
+			Remaining:  int(remainings[i]),
+			RetryAfter: dur(retryAfters[i]),
+			ResetAfter: dur(resetAfters[i]),
+		}
+	}
+	return results, nil
+}
+
+// SetLimit changes the limit enforced for key without resetting its
+// in-flight bucket state: the stored TAT is rescaled proportionally from
+// the emission interval implied by oldLimit to the one implied by
+// newLimit, so a caller who was partway through their old quota keeps the
+// same relative standing under the new one instead of having their bucket
+// reset to empty.
+func (b *Backend) SetLimit(ctx context.Context, key string, oldLimit, newLimit zenlimiter.Limit) error {
+	rateLimitKey := keyPrefix + key
+	now := float64(time.Now().UnixNano()) / 1e9
+
+	tat, err := b.getTat(ctx, rateLimitKey, now)
+	if err != nil {
+		return err
+	}
+
+	slack := tat - now
+	if slack <= 0 {
+		return nil
+	}
+
+	oldEmissionInterval := oldLimit.Period.Seconds() / float64(oldLimit.Rate)
+	newEmissionInterval := newLimit.Period.Seconds() / float64(newLimit.Rate)
+	newTat := now + slack*(newEmissionInterval/oldEmissionInterval)
+
+	_, _, err = b.client.Set(rateLimitKey, fmt.Sprintf("%f", newTat), echovault.SetOptions{
+		EX: int(math.Ceil(newTat - now)),
+	})
+	return err
+}
+
+// AllowMulti atomically checks n tokens against several tiered limits
+// sharing key and returns the most restrictive Result alongside every
+// tier's individual Result.
+func (b *Backend) AllowMulti(ctx context.Context, key string, limits []zenlimiter.Limit, n int) (*zenlimiter.Result, []*zenlimiter.Result, error) {
+	breakdown, err := b.AllowManyN(ctx, key, limits, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mostRestrictive(breakdown), breakdown, nil
+}
+
+// mostRestrictive returns the tier that would deny the request first: denied
+// beats allowed, and among denied tiers the one with the largest RetryAfter
+// wins. A tier that reports Allowed 0 only because another tier denied keeps
+// its own Remaining and has a RetryAfter of -1, so it never outranks a tier
+// that denied on its own.
+func mostRestrictive(results []*zenlimiter.Result) *zenlimiter.Result {
+	most := results[0]
+	for _, r := range results[1:] {
+		switch {
+		case most.Allowed > 0 && r.Allowed == 0:
+			most = r
+		case most.Allowed == 0 && r.Allowed == 0 && r.RetryAfter > most.RetryAfter:
+			most = r
+		case most.Allowed > 0 && r.Allowed > 0 && r.Remaining < most.Remaining:
+			most = r
+		}
+	}
+	return most
+}
+
 func (b *Backend) Reset(ctx context.Context, key string) error {
 	_, err := b.client.Del(keyPrefix + key)
 	if err != nil {
@@ -0,0 +1,301 @@
+package zlmemory
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/driftdev/zenlimiter"
+)
+
+const shardCount = 32
+
+var _ zenlimiter.LimiterProvider = (*Backend)(nil)
+
+// Backend implements zenlimiter.LimiterProvider entirely in process using a
+// GCRA implementation in Go, so callers can develop, test, and run
+// single-node deployments without Redis. It produces the exact same
+// Result shape as the Redis-backed providers, so backends are interchangeable.
+type Backend struct {
+	shards  [shardCount]*shard
+	multiMu sync.Mutex // serializes AllowMany/AllowManyN, which span several shards
+	done    chan struct{}
+}
+
+type shard struct {
+	mu   sync.Mutex
+	tats map[string]float64
+}
+
+// NewBackend creates a Backend and starts its background sweeper, which
+// evicts entries whose reset_after has elapsed every sweepInterval.
+func NewBackend(sweepInterval time.Duration) *Backend {
+	b := &Backend{done: make(chan struct{})}
+	for i := range b.shards {
+		b.shards[i] = &shard{tats: make(map[string]float64)}
+	}
+
+	go b.sweepLoop(sweepInterval)
+	return b
+}
+
+// Close stops the background sweeper. It is safe to keep using the Backend
+// afterward, but its entries will no longer be evicted automatically.
+func (b *Backend) Close() {
+	close(b.done)
+}
+
+func (b *Backend) sweepLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+			b.sweep()
+		}
+	}
+}
+
+func (b *Backend) sweep() {
+	now := nowSeconds()
+	for _, s := range b.shards {
+		s.mu.Lock()
+		for key, tat := range s.tats {
+			if tat <= now {
+				delete(s.tats, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (b *Backend) shardFor(key string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return b.shards[h.Sum32()%shardCount]
+}
+
+func (b *Backend) Allow(ctx context.Context, key string, limit zenlimiter.Limit) (*zenlimiter.Result, error) {
+	return b.AllowN(ctx, key, limit, 1)
+}
+
+func (b *Backend) AllowN(ctx context.Context, key string, limit zenlimiter.Limit, n int) (*zenlimiter.Result, error) {
+	s := b.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := nowSeconds()
+	emissionInterval := limit.Period.Seconds() / float64(limit.Rate)
+	burstOffset := emissionInterval * float64(limit.Burst)
+	increment := emissionInterval * float64(n)
+
+	tat, ok := s.tats[key]
+	if !ok {
+		tat = now
+	}
+	tat = math.Max(tat, now)
+
+	newTat := tat + increment
+	diff := now - (newTat - burstOffset)
+	remaining := diff / emissionInterval
+
+	if remaining < 0 {
+		return &zenlimiter.Result{
+			Limit:      limit,
+			Allowed:    0,
+			Remaining:  0,
+			RetryAfter: dur(-diff),
+			ResetAfter: dur(tat - now),
+		}, nil
+	}
+
+	resetAfter := newTat - now
+	if resetAfter > 0 {
+		s.tats[key] = newTat
+	}
+
+	return &zenlimiter.Result{
+		Limit:      limit,
+		Allowed:    n,
+		Remaining:  int(remaining),
+		RetryAfter: dur(-1),
+		ResetAfter: dur(resetAfter),
+	}, nil
+}
+
+func (b *Backend) AllowAtMost(ctx context.Context, key string, limit zenlimiter.Limit, n int) (*zenlimiter.Result, error) {
+	s := b.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := nowSeconds()
+	emissionInterval := limit.Period.Seconds() / float64(limit.Rate)
+	burstOffset := emissionInterval * float64(limit.Burst)
+
+	tat, ok := s.tats[key]
+	if !ok {
+		tat = now
+	}
+	tat = math.Max(tat, now)
+
+	diff := now - (tat - burstOffset)
+	remaining := diff / emissionInterval
+
+	if remaining < 1 {
+		return &zenlimiter.Result{
+			Limit:      limit,
+			Allowed:    0,
+			Remaining:  0,
+			RetryAfter: dur(emissionInterval - diff),
+			ResetAfter: dur(tat - now),
+		}, nil
+	}
+
+	allowed := n
+	if remaining < float64(n) {
+		allowed = int(remaining)
+		remaining = 0
+	} else {
+		remaining -= float64(allowed)
+	}
+
+	increment := emissionInterval * float64(allowed)
+	newTat := tat + increment
+	resetAfter := newTat - now
+	if resetAfter > 0 {
+		s.tats[key] = newTat
+	}
+
+	return &zenlimiter.Result{
+		Limit:      limit,
+		Allowed:    allowed,
+		Remaining:  int(remaining),
+		RetryAfter: dur(-1),
+		ResetAfter: dur(resetAfter),
+	}, nil
+}
+
+// AllowMany evaluates several limits sharing key in one atomic step; see
+// AllowManyN.
+func (b *Backend) AllowMany(ctx context.Context, key string, limits []zenlimiter.Limit) ([]*zenlimiter.Result, error) {
+	return b.AllowManyN(ctx, key, limits, 1)
+}
+
+// AllowManyN evaluates every limit in limits, each tracked under its own
+// per-tier key, and only commits state for any of them if all currently
+// allow n requests. The whole check-then-commit runs under a single mutex
+// since the tiers may span several shards.
+func (b *Backend) AllowManyN(ctx context.Context, key string, limits []zenlimiter.Limit, n int) ([]*zenlimiter.Result, error) {
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	b.multiMu.Lock()
+	defer b.multiMu.Unlock()
+
+	now := nowSeconds()
+	newTats := make([]float64, len(limits))
+	remainings := make([]float64, len(limits))
+	retryAfters := make([]float64, len(limits))
+	resetAfters := make([]float64, len(limits))
+	allowed := true
+
+	for i, limit := range limits {
+		tierKey := fmt.Sprintf("%s:%d", key, i)
+		s := b.shardFor(tierKey)
+
+		emissionInterval := limit.Period.Seconds() / float64(limit.Rate)
+		burstOffset := emissionInterval * float64(limit.Burst)
+		increment := emissionInterval * float64(n)
+
+		s.mu.Lock()
+		tat, ok := s.tats[tierKey]
+		s.mu.Unlock()
+		if !ok {
+			tat = now
+		}
+		tat = math.Max(tat, now)
+
+		newTat := tat + increment
+		diff := now - (newTat - burstOffset)
+		remaining := diff / emissionInterval
+
+		newTats[i] = newTat
+		remainings[i] = remaining
+
+		if remaining < 0 {
+			allowed = false
+			retryAfters[i] = -diff
+			resetAfters[i] = tat - now
+		} else {
+			retryAfters[i] = -1
+			resetAfters[i] = newTat - now
+		}
+	}
+
+	results := make([]*zenlimiter.Result, len(limits))
+	for i, limit := range limits {
+		if allowed && resetAfters[i] > 0 {
+			tierKey := fmt.Sprintf("%s:%d", key, i)
+			s := b.shardFor(tierKey)
+			s.mu.Lock()
+			s.tats[tierKey] = newTats[i]
+			s.mu.Unlock()
+		}
+
+		// remainings[i] < 0 means this tier individually would have denied
+		// the request; other tiers still report their true Remaining even
+		// when the overall request was denied by a different tier, so
+		// callers can tell which tier was the actual bottleneck. Allowed is
+		// always 0 when allowed is false, regardless of this tier's own
+		// headroom, since nothing was actually committed for any tier.
+		if remainings[i] < 0 {
+			results[i] = &zenlimiter.Result{
+				Limit:      limit,
+				Allowed:    0,
+				Remaining:  0,
+				RetryAfter: dur(retryAfters[i]),
+				ResetAfter: dur(resetAfters[i]),
+			}
+			continue
+		}
+
+		tierAllowed := n
+		if !allowed {
+			tierAllowed = 0
+		}
+		results[i] = &zenlimiter.Result{
+			Limit:      limit,
+			Allowed:    tierAllowed,
+			Remaining:  int(remainings[i]),
+			RetryAfter: dur(retryAfters[i]),
+			ResetAfter: dur(resetAfters[i]),
+		}
+	}
+	return results, nil
+}
+
+func (b *Backend) Reset(ctx context.Context, key string) error {
+	s := b.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tats, key)
+	return nil
+}
+
+func nowSeconds() float64 {
+	return float64(time.Now().UnixNano()) / 1e9
+}
+
+func dur(f float64) time.Duration {
+	if f == -1 {
+		return -1
+	}
+	return time.Duration(f * float64(time.Second))
+}
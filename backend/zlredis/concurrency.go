@@ -0,0 +1,142 @@
+package zlredis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrConcurrencyLimitExceeded is returned by ConcurrencyLimiter.Acquire when
+// the number of in-flight leases for a key has already reached max.
+var ErrConcurrencyLimitExceeded = errors.New("zlredis: concurrency limit exceeded")
+
+const concurrencyKeyPrefix = "concurrency:"
+
+// acquire sweeps expired leases from a key's sorted set and, if the
+// remaining cardinality is below max, inserts a new lease token scored by
+// its expiry timestamp. now is read from the Redis server's own clock
+// rather than passed in from the Go client, so client/server clock skew
+// can't sweep leases at the wrong time. The set's own TTL is bumped to the
+// furthest-out lease's expiry with EXPIREAT ... GT rather than
+// unconditionally set to this call's ttl, so a short-lived lease acquired
+// after a long-lived one can't shrink the set's TTL and cause the whole set
+// (including the still in-flight long lease) to expire early.
+var acquire = redis.NewScript(`
+local set_key = KEYS[1]
+local max = tonumber(ARGV[1])
+local ttl = tonumber(ARGV[2])
+local token = ARGV[3]
+
+local now = redis.call("TIME")
+now = tonumber(now[1])
+
+redis.call("ZREMRANGEBYSCORE", set_key, "-inf", now)
+
+local count = redis.call("ZCARD", set_key)
+if count >= max then
+  return 0
+end
+
+redis.call("ZADD", set_key, now + ttl, token)
+
+local max_score = redis.call("ZREVRANGE", set_key, 0, 0, "WITHSCORES")[2]
+redis.call("EXPIREAT", set_key, math.ceil(tonumber(max_score)), "GT")
+return 1
+`)
+
+var release = redis.NewScript(`
+return redis.call("ZREM", KEYS[1], ARGV[1])
+`)
+
+// extend pushes a lease's expiry out by ttl from now, but only if the
+// lease's token is still present in the set - so a lease that has already
+// expired and been swept by a concurrent Acquire's ZREMRANGEBYSCORE isn't
+// silently resurrected. Like acquire, the set's TTL is bumped with
+// EXPIREAT ... GT rather than overwritten, so extending one lease can't
+// shrink the set's TTL out from under a different, longer-lived lease.
+var extend = redis.NewScript(`
+local set_key = KEYS[1]
+local ttl = tonumber(ARGV[1])
+local token = ARGV[2]
+
+if not redis.call("ZSCORE", set_key, token) then
+  return 0
+end
+
+local now = redis.call("TIME")
+now = tonumber(now[1])
+
+redis.call("ZADD", set_key, now + ttl, token)
+
+local max_score = redis.call("ZREVRANGE", set_key, 0, 0, "WITHSCORES")[2]
+redis.call("EXPIREAT", set_key, math.ceil(tonumber(max_score)), "GT")
+return 1
+`)
+
+// ConcurrencyLimiter limits the number of concurrent in-flight operations
+// for a key using a Redis sorted set of lease tokens scored by expiry.
+type ConcurrencyLimiter struct {
+	client RedisClient
+}
+
+// NewConcurrencyLimiter creates and returns a new ConcurrencyLimiter backed
+// by the given RedisClient.
+func NewConcurrencyLimiter(client RedisClient) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{client: client}
+}
+
+// Lease represents a granted slot in a ConcurrencyLimiter. Callers must
+// Release it once the in-flight operation completes; otherwise the slot is
+// reclaimed automatically once its TTL elapses.
+type Lease struct {
+	client RedisClient
+	setKey string
+	token  string
+}
+
+// Release frees the lease's slot immediately instead of waiting for its TTL
+// to expire.
+func (l *Lease) Release(ctx context.Context) error {
+	return release.Run(ctx, l.client, []string{l.setKey}, l.token).Err()
+}
+
+// Extend pushes the lease's expiry out by ttl from now, for callers whose
+// in-flight operation is taking longer than originally expected. It is a
+// no-op if the lease has already expired and been swept.
+func (l *Lease) Extend(ctx context.Context, ttl time.Duration) error {
+	return extend.Run(ctx, l.client, []string{l.setKey}, int(ttl.Seconds()), l.token).Err()
+}
+
+// Acquire attempts to reserve a slot for key, allowing at most max
+// concurrent leases at a time. Expired leases are swept before the count is
+// checked, so crashed callers that never call Release don't leak slots.
+func (c *ConcurrencyLimiter) Acquire(ctx context.Context, key string, max int, ttl time.Duration) (*Lease, error) {
+	token, err := newLeaseToken()
+	if err != nil {
+		return nil, err
+	}
+
+	setKey := concurrencyKeyPrefix + key
+	v, err := acquire.Run(ctx, c.client, []string{setKey}, max, int(ttl.Seconds()), token).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if v.(int64) == 0 {
+		return nil, ErrConcurrencyLimitExceeded
+	}
+
+	return &Lease{client: c.client, setKey: setKey, token: token}, nil
+}
+
+func newLeaseToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
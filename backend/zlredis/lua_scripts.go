@@ -0,0 +1,121 @@
+package zlredis
+
+import "github.com/redis/go-redis/v9"
+
+// allowMany is a Redis Lua script that atomically evaluates several rate
+// limits in a single round trip. A request is only counted against every
+// limit if all of them currently allow it; if any limit would deny the
+// request, no bucket state is mutated for any of them.
+var allowMany = redis.NewScript(`
+redis.replicate_commands()
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local n = tonumber(ARGV[1])
+local new_tats = {}
+local remainings = {}
+local retry_afters = {}
+local reset_afters = {}
+local allowed = true
+
+for i = 0, n - 1 do
+  local base = 2 + i * 4
+  local burst = tonumber(ARGV[base])
+  local rate = tonumber(ARGV[base + 1])
+  local period = tonumber(ARGV[base + 2])
+  local cost = tonumber(ARGV[base + 3])
+
+  local emission_interval = period / rate
+  local increment = emission_interval * cost
+  local burst_offset = emission_interval * burst
+
+  local tat = redis.call("GET", KEYS[i + 1])
+  if not tat then
+    tat = now
+  else
+    tat = tonumber(tat)
+  end
+  tat = math.max(tat, now)
+
+  local new_tat = tat + increment
+  local diff = now - (new_tat - burst_offset)
+  local remaining = diff / emission_interval
+
+  new_tats[i + 1] = new_tat
+  remainings[i + 1] = remaining
+
+  if remaining < 0 then
+    allowed = false
+    retry_afters[i + 1] = diff * -1
+    reset_afters[i + 1] = tat - now
+  else
+    retry_afters[i + 1] = -1
+    reset_afters[i + 1] = new_tat - now
+  end
+end
+
+local out = {}
+for i = 0, n - 1 do
+  local idx = i + 1
+  local cost = tonumber(ARGV[2 + i * 4 + 3])
+
+  if allowed and reset_afters[idx] > 0 then
+    redis.call("SET", KEYS[idx], new_tats[idx], "EX", math.ceil(reset_afters[idx]))
+  end
+
+  if remainings[idx] < 0 then
+    out[idx] = {0, 0, tostring(retry_afters[idx]), tostring(reset_afters[idx])}
+  elseif not allowed then
+    out[idx] = {0, remainings[idx], tostring(retry_afters[idx]), tostring(reset_afters[idx])}
+  else
+    out[idx] = {cost, remainings[idx], tostring(retry_afters[idx]), tostring(reset_afters[idx])}
+  end
+end
+return out
+`)
+
+// setLimit is a Redis Lua script that rescales a key's stored TAT from the
+// emission interval implied by the old limit to the one implied by the new
+// limit, so an in-flight bucket keeps its relative "debt" instead of being
+// reset to empty (which would let an abuser refill instantly) or left
+// interpreted under the wrong rate (which would misreport remaining/retry).
+//
+// Parameters used in the script:
+//   - KEYS[1]: The key used for rate limiting in Redis.
+//   - ARGV[1]: The old period (duration in seconds) used to derive the old emission interval.
+//   - ARGV[2]: The old rate.
+//   - ARGV[3]: The new period.
+//   - ARGV[4]: The new rate.
+var setLimit = redis.NewScript(`
+redis.replicate_commands()
+
+local rate_limit_key = KEYS[1]
+local old_period = tonumber(ARGV[1])
+local old_rate = tonumber(ARGV[2])
+local new_period = tonumber(ARGV[3])
+local new_rate = tonumber(ARGV[4])
+
+local old_emission_interval = old_period / old_rate
+local new_emission_interval = new_period / new_rate
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local tat = redis.call("GET", rate_limit_key)
+if not tat then
+  return 1
+end
+tat = tonumber(tat)
+
+local slack = tat - now
+if slack <= 0 then
+  return 1
+end
+
+local new_tat = now + slack * (new_emission_interval / old_emission_interval)
+redis.call("SET", rate_limit_key, new_tat, "EX", math.ceil(new_tat - now))
+return 1
+`)
@@ -0,0 +1,127 @@
+package zlredis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/driftdev/zenlimit"
+	"github.com/redis/go-redis/v9"
+)
+
+var penalize = redis.NewScript(`
+redis.replicate_commands()
+
+local rate_limit_key = KEYS[1]
+local rate = ARGV[2]
+local period = ARGV[3]
+local cost = tonumber(ARGV[4])
+
+local emission_interval = period / rate
+local increment = emission_interval * cost
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local tat = redis.call("GET", rate_limit_key)
+if not tat then
+  tat = now
+else
+  tat = tonumber(tat)
+end
+tat = math.max(tat, now)
+
+local new_tat = tat + increment
+local reset_after = new_tat - now
+redis.call("SET", rate_limit_key, new_tat, "EX", math.ceil(reset_after))
+
+return tostring(reset_after)
+`)
+
+// peekSrc is run via EVAL_RO rather than wrapped in a redis.Script, which
+// only issues read-write EVAL/EVALSHA.
+const peekSrc = `
+local rate_limit_key = KEYS[1]
+local burst = ARGV[1]
+local rate = ARGV[2]
+local period = ARGV[3]
+
+local emission_interval = period / rate
+local burst_offset = emission_interval * burst
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local tat = redis.call("GET", rate_limit_key)
+if not tat then
+  tat = now
+else
+  tat = tonumber(tat)
+end
+tat = math.max(tat, now)
+
+local diff = now - (tat - burst_offset)
+local remaining = diff / emission_interval
+if remaining < 0 then
+  remaining = 0
+end
+
+return {remaining, tostring(tat - now)}
+`
+
+// Penalize consumes cost tokens from key's bucket unconditionally, even when
+// cost exceeds limit.Burst, pushing the bucket into a quarantine state. It
+// returns the resulting ResetAfter: how long until the bucket has fully
+// drained. A single request is allowed again (Burst-1) emission intervals
+// (Period/Rate) before that.
+func (b *Backend) Penalize(ctx context.Context, key string, limit zenlimit.Limit, cost int) (time.Duration, error) {
+	values := []interface{}{limit.Burst, limit.Rate, limit.Period.Seconds(), cost}
+	v, err := penalize.Run(ctx, b.client, []string{keyPrefix + key}, values...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	resetAfter, err := strconv.ParseFloat(v.(string), 64)
+	if err != nil {
+		return 0, err
+	}
+	return dur(resetAfter), nil
+}
+
+// SetTAT administratively quarantines key until the given time.
+func (b *Backend) SetTAT(ctx context.Context, key string, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return b.Reset(ctx, key)
+	}
+
+	tat := float64(until.Unix()) - 1483228800 + float64(until.Nanosecond())/1e9
+	_, err := b.client.Eval(ctx, "return redis.call('SET', KEYS[1], ARGV[1], 'EX', math.ceil(tonumber(ARGV[2])))", []string{keyPrefix + key}, tat, ttl.Seconds()).Result()
+	return err
+}
+
+// Peek reports the current state of key's bucket without consuming any
+// tokens, via EVAL_RO so it can be served from a read replica.
+func (b *Backend) Peek(ctx context.Context, key string, limit zenlimit.Limit) (*zenlimit.Result, error) {
+	values := []interface{}{limit.Burst, limit.Rate, limit.Period.Seconds()}
+	v, err := b.client.EvalRO(ctx, peekSrc, []string{keyPrefix + key}, values...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := v.([]interface{})
+	resetAfter, err := strconv.ParseFloat(fields[1].(string), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zenlimit.Result{
+		Limit:      limit,
+		Allowed:    0,
+		Remaining:  int(fields[0].(int64)),
+		RetryAfter: -1,
+		ResetAfter: dur(resetAfter),
+	}, nil
+}
@@ -2,6 +2,7 @@ package zlredis
 
 import (
 	"context"
+	"fmt"
 	"github.com/driftdev/zenlimit"
 	"github.com/redis/go-redis/v9"
 	"strconv"
@@ -102,6 +103,73 @@ func (b *Backend) AllowAtMost(
 	return res, nil
 }
 
+func (b *Backend) AllowMany(ctx context.Context, key string, limits []zenlimit.Limit) ([]*zenlimit.Result, error) {
+	return b.AllowManyN(ctx, key, limits, 1)
+}
+
+func (b *Backend) AllowManyN(
+	ctx context.Context,
+	key string,
+	limits []zenlimit.Limit,
+	n int,
+) ([]*zenlimit.Result, error) {
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(limits))
+	values := make([]interface{}, 0, 1+len(limits)*4)
+	values = append(values, len(limits))
+	for i, limit := range limits {
+		keys[i] = fmt.Sprintf("%s%s:%d", keyPrefix, key, i)
+		values = append(values, limit.Burst, limit.Rate, limit.Period.Seconds(), n)
+	}
+
+	v, err := allowMany.Run(ctx, b.client, keys, values...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := v.([]interface{})
+	results := make([]*zenlimit.Result, len(limits))
+	for i, row := range rows {
+		fields := row.([]interface{})
+
+		retryAfter, err := strconv.ParseFloat(fields[2].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+
+		resetAfter, err := strconv.ParseFloat(fields[3].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+
+		results[i] = &zenlimit.Result{
+			Limit:      limits[i],
+			Allowed:    int(fields[0].(int64)),
+			Remaining:  int(fields[1].(int64)),
+			RetryAfter: dur(retryAfter),
+			ResetAfter: dur(resetAfter),
+		}
+	}
+	return results, nil
+}
+
+// SetLimit changes the Rate/Burst/Period enforced for key without resetting
+// its in-flight bucket state: the stored TAT is rescaled proportionally
+// from the emission interval implied by oldLimit to the one implied by
+// newLimit, so a caller who was partway through their old quota keeps the
+// same relative standing under the new one. A plain Reset would instead let
+// an abuser refill their bucket instantly.
+func (b *Backend) SetLimit(ctx context.Context, key string, oldLimit, newLimit zenlimit.Limit) error {
+	values := []interface{}{
+		oldLimit.Period.Seconds(), oldLimit.Rate,
+		newLimit.Period.Seconds(), newLimit.Rate,
+	}
+	return setLimit.Run(ctx, b.client, []string{keyPrefix + key}, values...).Err()
+}
+
 func (b *Backend) Reset(ctx context.Context, key string) error {
 	return b.client.Del(ctx, keyPrefix+key).Err()
 }
@@ -0,0 +1,25 @@
+package surgelimit
+
+import (
+	"context"
+	"time"
+)
+
+// LimitBytesPerSecond returns a Limit whose Rate and Burst are expressed in
+// bytes rather than requests, for use with AllowBytes or the zenio
+// Reader/Writer wrappers that pace I/O to a bandwidth cap.
+func LimitBytesPerSecond(bytesPerSecond int) Limit {
+	return Limit{
+		Rate:   bytesPerSecond,
+		Period: time.Second,
+		Burst:  bytesPerSecond,
+	}
+}
+
+// AllowBytes is like AllowN but the cost is measured in bytes transferred
+// rather than requests. The GCRA math already supports fractional/
+// non-request increments, so this is a thin naming convenience over AllowN
+// for callers pacing bandwidth with a Limit built from LimitBytesPerSecond.
+func (l *Limiter) AllowBytes(ctx context.Context, key string, limit Limit, bytes int64) (*Result, error) {
+	return l.AllowN(ctx, key, limit, int(bytes))
+}
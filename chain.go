@@ -0,0 +1,122 @@
+package zenlimiter
+
+import "context"
+
+var _ LimiterProvider = (*ChainProvider)(nil)
+
+// ChainProvider queries a sequence of LimiterProvider backends in order,
+// typically a fast local backend followed by a slower shared one, and only
+// consults a later backend when every earlier one would allow the request.
+// This lets a shared backend (e.g. Redis) absorb only the requests that
+// already passed the local burst check, cutting its QPS for hot keys while
+// keeping global correctness.
+type ChainProvider struct {
+	providers []LimiterProvider
+}
+
+// Chain builds a ChainProvider out of providers, queried in the given
+// order.
+func Chain(providers ...LimiterProvider) *ChainProvider {
+	return &ChainProvider{providers: providers}
+}
+
+func (c *ChainProvider) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return c.AllowN(ctx, key, limit, 1)
+}
+
+func (c *ChainProvider) AllowN(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	var merged *Result
+	for _, p := range c.providers {
+		result, err := p.AllowN(ctx, key, limit, n)
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeResult(merged, result)
+		if result.Allowed == 0 {
+			break
+		}
+	}
+	return merged, nil
+}
+
+func (c *ChainProvider) AllowAtMost(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	var merged *Result
+	for _, p := range c.providers {
+		result, err := p.AllowAtMost(ctx, key, limit, n)
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeResult(merged, result)
+		if result.Allowed == 0 {
+			break
+		}
+	}
+	return merged, nil
+}
+
+func (c *ChainProvider) AllowMany(ctx context.Context, key string, limits []Limit) ([]*Result, error) {
+	return c.AllowManyN(ctx, key, limits, 1)
+}
+
+func (c *ChainProvider) AllowManyN(ctx context.Context, key string, limits []Limit, n int) ([]*Result, error) {
+	var merged []*Result
+	for _, p := range c.providers {
+		results, err := p.AllowManyN(ctx, key, limits, n)
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeResults(merged, results)
+		if anyDenied(results) {
+			break
+		}
+	}
+	return merged, nil
+}
+
+// Reset clears every provider's state for key.
+func (c *ChainProvider) Reset(ctx context.Context, key string) error {
+	for _, p := range c.providers {
+		if err := p.Reset(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeResult combines two Results for the same key, keeping whichever is
+// more restrictive: denied over allowed, then fewer Remaining.
+func mergeResult(a, b *Result) *Result {
+	if a == nil {
+		return b
+	}
+	if a.Allowed == 0 {
+		return a
+	}
+	if b.Allowed == 0 {
+		return b
+	}
+	if b.Remaining < a.Remaining {
+		return b
+	}
+	return a
+}
+
+func mergeResults(a, b []*Result) []*Result {
+	if a == nil {
+		return b
+	}
+	merged := make([]*Result, len(a))
+	for i := range a {
+		merged[i] = mergeResult(a[i], b[i])
+	}
+	return merged
+}
+
+func anyDenied(results []*Result) bool {
+	for _, r := range results {
+		if r.Allowed == 0 {
+			return true
+		}
+	}
+	return false
+}
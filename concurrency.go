@@ -0,0 +1,168 @@
+package surgelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrConcurrencyLimitExceeded is returned by Acquire when the number of
+// in-flight leases for a key has already reached max.
+var ErrConcurrencyLimitExceeded = errors.New("surgelimit: concurrency limit exceeded")
+
+// acquire is a Redis Lua script that atomically sweeps expired leases from a
+// key's sorted set and, if the remaining cardinality is below max, inserts a
+// new lease token scored by its expiry timestamp. now is read from the Redis
+// server's own clock (like every other script in this package) rather than
+// passed in from the Go client, so client/server clock skew can't sweep
+// leases at the wrong time. The set's own TTL is bumped to the furthest-out
+// lease's expiry with EXPIREAT ... GT rather than unconditionally set to
+// this call's ttl, so a short-lived lease acquired after a long-lived one
+// can't shrink the set's TTL and cause the whole set (including the still
+// in-flight long lease) to expire early.
+//
+// Parameters used in the script:
+//   - KEYS[1]: The sorted set holding in-flight lease tokens for the key.
+//   - ARGV[1]: The maximum number of concurrent leases allowed.
+//   - ARGV[2]: The TTL, in seconds, applied to the new lease.
+//   - ARGV[3]: The lease token to insert if the request is granted.
+var acquire = redis.NewScript(`
+local set_key = KEYS[1]
+local max = tonumber(ARGV[1])
+local ttl = tonumber(ARGV[2])
+local token = ARGV[3]
+
+local now = redis.call("TIME")
+now = tonumber(now[1])
+
+redis.call("ZREMRANGEBYSCORE", set_key, "-inf", now)
+
+local count = redis.call("ZCARD", set_key)
+if count >= max then
+  return 0
+end
+
+redis.call("ZADD", set_key, now + ttl, token)
+
+local max_score = redis.call("ZREVRANGE", set_key, 0, 0, "WITHSCORES")[2]
+redis.call("EXPIREAT", set_key, math.ceil(tonumber(max_score)), "GT")
+return 1
+`)
+
+// extend is a Redis Lua script that pushes a lease's expiry out by ttl from
+// now, but only if the lease's token is still present in the set - so a
+// lease that has already expired and been swept by a concurrent Acquire's
+// ZREMRANGEBYSCORE isn't silently resurrected. Like acquire, the set's TTL
+// is bumped with EXPIREAT ... GT rather than overwritten, so extending one
+// lease can't shrink the set's TTL out from under a different, longer-lived
+// lease.
+//
+// Parameters used in the script:
+//   - KEYS[1]: The sorted set holding in-flight lease tokens for the key.
+//   - ARGV[1]: The TTL, in seconds, to extend the lease by.
+//   - ARGV[2]: The lease token to extend.
+var extend = redis.NewScript(`
+local set_key = KEYS[1]
+local ttl = tonumber(ARGV[1])
+local token = ARGV[2]
+
+if not redis.call("ZSCORE", set_key, token) then
+  return 0
+end
+
+local now = redis.call("TIME")
+now = tonumber(now[1])
+
+redis.call("ZADD", set_key, now + ttl, token)
+
+local max_score = redis.call("ZREVRANGE", set_key, 0, 0, "WITHSCORES")[2]
+redis.call("EXPIREAT", set_key, math.ceil(tonumber(max_score)), "GT")
+return 1
+`)
+
+// ConcurrencyLimiter limits the number of concurrent in-flight operations
+// for a key, as opposed to Limiter and LeakyBucketLimiter which limit the
+// rate of operations over time. It is backed by a Redis sorted set of lease
+// tokens scored by their expiry timestamp, so leases auto-expire and crashed
+// callers cannot leak slots.
+type ConcurrencyLimiter struct {
+	client  redis.UniversalClient
+	Options Options
+}
+
+// NewConcurrencyLimiter creates and returns a new ConcurrencyLimiter backed
+// by the given Redis client.
+func NewConcurrencyLimiter(client redis.UniversalClient, options Options) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		client:  client,
+		Options: options,
+	}
+}
+
+// Lease represents a granted slot in a ConcurrencyLimiter. Callers must
+// Release it once the in-flight operation completes; otherwise the slot is
+// reclaimed automatically once ttl elapses.
+type Lease struct {
+	client redis.UniversalClient
+	setKey string
+	token  string
+}
+
+// Release frees the lease's slot immediately instead of waiting for its TTL
+// to expire.
+func (l *Lease) Release(ctx context.Context) error {
+	return l.client.ZRem(ctx, l.setKey, l.token).Err()
+}
+
+// Extend pushes the lease's expiry out by ttl from now, for callers whose
+// in-flight operation is taking longer than originally expected. It is a
+// no-op if the lease has already expired and been swept.
+func (l *Lease) Extend(ctx context.Context, ttl time.Duration) error {
+	return extend.Run(ctx, l.client, []string{l.setKey}, int(ttl.Seconds()), l.token).Err()
+}
+
+// Acquire attempts to reserve a slot for key, allowing at most max
+// concurrent leases at a time. Expired leases (older than their ttl) are
+// swept before the count is checked, so crashed callers that never call
+// Release don't leak slots forever.
+//
+// Parameters:
+//   - ctx: The context to control cancellation and timeouts.
+//   - key: The unique identifier whose in-flight operations are being limited.
+//   - max: The maximum number of concurrent leases allowed for key.
+//   - ttl: How long a lease is held before it is considered expired.
+//
+// Returns:
+//   - *Lease: The granted lease, which must be Released when the operation completes.
+//   - error: ErrConcurrencyLimitExceeded if max concurrent leases are already held,
+//     or any error encountered while running the underlying Lua script.
+func (c *ConcurrencyLimiter) Acquire(ctx context.Context, key string, max int, ttl time.Duration) (*Lease, error) {
+	token, err := newLeaseToken()
+	if err != nil {
+		return nil, err
+	}
+
+	setKey := keyWithPrefix(c.Options.KeyPrefix, key)
+	v, err := acquire.Run(ctx, c.client, []string{setKey}, max, int(ttl.Seconds()), token).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if v.(int64) == 0 {
+		return nil, ErrConcurrencyLimitExceeded
+	}
+
+	return &Lease{client: c.client, setKey: setKey, token: token}, nil
+}
+
+func newLeaseToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,140 @@
+package surgelimit
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fixedWindowAllowN is a Redis Lua script implementing the fixed-window
+// counter algorithm: requests are counted against a key suffixed by the
+// current window index (floor(now/period)), so the counter resets cleanly
+// at a fixed boundary instead of rolling continuously. This is cheaper than
+// a sliding-window log (one counter instead of one ZSET member per request)
+// at the cost of allowing up to 2x limit requests across a window boundary.
+//
+// The script performs the following operations:
+//   - Computes the current window's key from floor(now/period).
+//   - Increments that window's counter by cost and refreshes its TTL.
+//   - Rolls the increment back and denies the request if the counter now exceeds limit.
+//
+// Parameters used in the script:
+//   - KEYS[1]: The key prefix used for rate limiting in Redis (the window index is appended).
+//   - ARGV[1]: The limit (maximum number of requests allowed within the window).
+//   - ARGV[2]: The period, in seconds, of each window.
+//   - ARGV[3]: The cost (number of requests being attempted).
+var fixedWindowAllowN = redis.NewScript(`
+redis.replicate_commands()
+
+local key_prefix = KEYS[1]
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+
+local now = redis.call("TIME")
+now = now[1]
+
+local window = math.floor(now / period)
+local window_key = key_prefix .. ":" .. window
+local reset_after = ((window + 1) * period) - now
+
+local count = redis.call("INCRBY", window_key, cost)
+redis.call("EXPIRE", window_key, period)
+
+if count > limit then
+  redis.call("DECRBY", window_key, cost)
+  return {
+    0, -- allowed
+    math.max(0, limit - (count - cost)), -- remaining
+    tostring(reset_after),
+    tostring(reset_after),
+  }
+end
+
+return {cost, limit - count, tostring(-1), tostring(reset_after)}
+`)
+
+// fixedWindowReset is a Redis Lua script that deletes the counter for the
+// window key_prefix currently falls in, computing the window index from the
+// Redis server's own clock (like fixedWindowAllowN) rather than the Go
+// client's, so clock skew can't delete the wrong window's counter.
+//
+// Parameters used in the script:
+//   - KEYS[1]: The key prefix used for rate limiting in Redis (the window index is appended).
+//   - ARGV[1]: The period, in seconds, of each window.
+var fixedWindowReset = redis.NewScript(`
+local key_prefix = KEYS[1]
+local period = tonumber(ARGV[1])
+
+local now = redis.call("TIME")
+now = now[1]
+
+local window = math.floor(now / period)
+return redis.call("DEL", key_prefix .. ":" .. window)
+`)
+
+// FixedWindowLimiter is a sibling of LeakyBucketLimiter exposing the same
+// "N per period" semantics as SlidingWindowLimiter, but implemented as a
+// single counter per fixed window boundary rather than a log of individual
+// request timestamps. It shares Limit, Result, and Options; Limit.Burst is
+// not consulted.
+type FixedWindowLimiter struct {
+	client  redis.UniversalClient
+	Options Options
+}
+
+// NewFixedWindowLimiter creates and returns a new FixedWindowLimiter using
+// the provided Redis client and options.
+func NewFixedWindowLimiter(client redis.UniversalClient, options Options) *FixedWindowLimiter {
+	return &FixedWindowLimiter{
+		client:  client,
+		Options: options,
+	}
+}
+
+// Allow attempts to allow a single request for key under limit. This is a
+// convenience method that calls AllowN with n set to 1.
+func (l *FixedWindowLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return l.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN attempts to allow n requests for key under limit, denying and
+// rolling back the whole request if it would push the current window's
+// counter past limit.
+func (l *FixedWindowLimiter) AllowN(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	values := []interface{}{limit.Rate, limit.Period.Seconds(), n}
+	v, err := fixedWindowAllowN.Run(ctx, l.client, []string{keyWithPrefix(l.Options.KeyPrefix, key)}, values...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	results := v.([]interface{})
+
+	retryAfter, err := strconv.ParseFloat(results[2].(string), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	resetAfter, err := strconv.ParseFloat(results[3].(string), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Limit:      limit,
+		Allowed:    int(results[0].(int64)),
+		Remaining:  int(results[1].(int64)),
+		RetryAfter: dur(retryAfter),
+		ResetAfter: dur(resetAfter),
+	}, nil
+}
+
+// Reset clears key's counter for the window it currently falls in. limit is
+// required (unlike LeakyBucketLimiter.Reset) because the counter actually
+// stored in Redis lives at key:<window-index>, and the window index depends
+// on limit.Period.
+func (l *FixedWindowLimiter) Reset(ctx context.Context, key string, limit Limit) error {
+	values := []interface{}{limit.Period.Seconds()}
+	return fixedWindowReset.Run(ctx, l.client, []string{keyWithPrefix(l.Options.KeyPrefix, key)}, values...).Err()
+}
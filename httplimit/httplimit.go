@@ -0,0 +1,140 @@
+// Package httplimit provides net/http middleware that enforces a
+// surgelimit.Limiter rate limit and reports it via the IETF draft
+// RateLimit-* response headers.
+package httplimit
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/driftdev/surgelimit"
+)
+
+// KeyFunc extracts the rate-limiting key for an inbound request, e.g. the
+// caller's IP address, an API key, or a tenant ID.
+type KeyFunc func(r *http.Request) string
+
+// Exemptions lets callers skip rate limiting entirely for trusted traffic,
+// such as internal health checks or known partner origins.
+type Exemptions struct {
+	// Origins exempts requests whose Origin header exactly matches one of these values.
+	Origins []string
+	// UserAgents exempts requests whose User-Agent header exactly matches one of these values.
+	UserAgents []string
+	// CIDRs exempts requests whose KeyByIP-resolved address falls within one of these networks.
+	CIDRs []netip.Prefix
+}
+
+func (e Exemptions) isExempt(r *http.Request, ip string) bool {
+	for _, origin := range e.Origins {
+		if r.Header.Get("Origin") == origin {
+			return true
+		}
+	}
+	for _, ua := range e.UserAgents {
+		if r.Header.Get("User-Agent") == ua {
+			return true
+		}
+	}
+	if ip == "" || len(e.CIDRs) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	for _, cidr := range e.CIDRs {
+		if cidr.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware returns an http.Handler wrapper that calls limiter.Allow for
+// every request using the key returned by keyFunc. trustedCIDRs is used only
+// to resolve the request's IP for exemptions.CIDRs matching; it has no
+// effect on keyFunc itself (pass KeyByIP(trustedCIDRs) as keyFunc to also
+// key rate limiting by the trusted-proxy-aware IP). Allowed requests get the
+// RateLimit-Limit, RateLimit-Remaining, and RateLimit-Reset headers set on
+// the response; denied requests additionally get a Retry-After header and a
+// 429 Too Many Requests status.
+func Middleware(limiter *surgelimit.Limiter, keyFunc KeyFunc, limit surgelimit.Limit, trustedCIDRs []netip.Prefix, exemptions Exemptions) func(http.Handler) http.Handler {
+	ipFunc := KeyByIP(trustedCIDRs)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ipFunc(r)
+			if exemptions.isExempt(r, ip) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := limiter.Allow(r.Context(), keyFunc(r), limit)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			setHeaders(w, result)
+			if result.Allowed == 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func setHeaders(w http.ResponseWriter, result *surgelimit.Result) {
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit.Rate))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(int(result.ResetAfter.Seconds())))
+}
+
+// KeyByIP returns a KeyFunc that honors X-Forwarded-For and X-Real-IP only
+// when r.RemoteAddr falls within one of trustedCIDRs, falling back to
+// RemoteAddr otherwise. This prevents untrusted clients from spoofing their
+// rate-limit key via the forwarding headers.
+func KeyByIP(trustedCIDRs []netip.Prefix) KeyFunc {
+	return func(r *http.Request) string {
+		remoteHost, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			remoteHost = r.RemoteAddr
+		}
+
+		if isTrustedProxy(remoteHost, trustedCIDRs) {
+			if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+				if idx := strings.IndexByte(fwd, ','); idx >= 0 {
+					return strings.TrimSpace(fwd[:idx])
+				}
+				return strings.TrimSpace(fwd)
+			}
+			if real := r.Header.Get("X-Real-IP"); real != "" {
+				return real
+			}
+		}
+
+		return remoteHost
+	}
+}
+
+func isTrustedProxy(host string, trustedCIDRs []netip.Prefix) bool {
+	if len(trustedCIDRs) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	for _, cidr := range trustedCIDRs {
+		if cidr.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
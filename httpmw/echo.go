@@ -0,0 +1,38 @@
+package httpmw
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/driftdev/surgelimit"
+)
+
+// EchoKeyFunc extracts the rate-limiting key for an inbound echo request.
+type EchoKeyFunc func(c echo.Context) string
+
+// EchoMiddleware is the echo.MiddlewareFunc equivalent of Middleware.
+func EchoMiddleware(limiter *surgelimit.Limiter, keyFn EchoKeyFunc, limit surgelimit.Limit, opts ...Option) echo.MiddlewareFunc {
+	cfg := &config{deniedHandler: defaultDeniedHandler}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			result, err := limiter.AllowN(c.Request().Context(), keyFn(c), limit, 1)
+			if err != nil {
+				return err
+			}
+
+			setHeaders(c.Response(), result)
+			if result.Allowed == 0 {
+				c.Response().Header().Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+				cfg.deniedHandler(c.Response(), c.Request(), result)
+				return nil
+			}
+
+			return next(c)
+		}
+	}
+}
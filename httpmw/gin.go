@@ -0,0 +1,38 @@
+package httpmw
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/driftdev/surgelimit"
+)
+
+// GinKeyFunc extracts the rate-limiting key for an inbound gin request.
+type GinKeyFunc func(c *gin.Context) string
+
+// GinMiddleware is the gin.HandlerFunc equivalent of Middleware.
+func GinMiddleware(limiter *surgelimit.Limiter, keyFn GinKeyFunc, limit surgelimit.Limit, opts ...Option) gin.HandlerFunc {
+	cfg := &config{deniedHandler: defaultDeniedHandler}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(c *gin.Context) {
+		result, err := limiter.AllowN(c.Request.Context(), keyFn(c), limit, 1)
+		if err != nil {
+			c.AbortWithError(500, err)
+			return
+		}
+
+		setHeaders(c.Writer, result)
+		if result.Allowed == 0 {
+			c.Writer.Header().Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+			cfg.deniedHandler(c.Writer, c.Request, result)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,76 @@
+// Package httpmw provides net/http (and echo/gin) middleware that enforces a
+// surgelimit.Limiter rate limit and reports it via the IETF draft
+// RateLimit-* response headers, so callers don't have to reimplement the
+// headers and key-extraction boilerplate themselves.
+package httpmw
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/driftdev/surgelimit"
+)
+
+// KeyFunc extracts the rate-limiting key for an inbound request, e.g. the
+// caller's IP address, an API key, or a tenant ID.
+type KeyFunc func(r *http.Request) string
+
+// config holds the options applied by Option; DeniedHandler defaults to
+// writing a plain-text 429 body.
+type config struct {
+	deniedHandler func(w http.ResponseWriter, r *http.Request, result *surgelimit.Result)
+}
+
+// Option customizes the middleware returned by Middleware.
+type Option func(*config)
+
+// WithDeniedHandler overrides the response written when a request is denied.
+// The RateLimit-* and Retry-After headers are already set on w by the time
+// fn is called.
+func WithDeniedHandler(fn func(w http.ResponseWriter, r *http.Request, result *surgelimit.Result)) Option {
+	return func(c *config) {
+		c.deniedHandler = fn
+	}
+}
+
+func defaultDeniedHandler(w http.ResponseWriter, r *http.Request, result *surgelimit.Result) {
+	http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+}
+
+// Middleware returns an http.Handler wrapper that calls limiter.AllowN(…, 1)
+// for every request using the key returned by keyFn. Allowed requests get
+// the RateLimit-Limit, RateLimit-Remaining, and RateLimit-Reset headers set
+// on the response; denied requests additionally get a Retry-After header
+// and, by default, a 429 Too Many Requests status with a plain-text body -
+// override the body with WithDeniedHandler.
+func Middleware(limiter *surgelimit.Limiter, keyFn KeyFunc, limit surgelimit.Limit, opts ...Option) func(http.Handler) http.Handler {
+	cfg := &config{deniedHandler: defaultDeniedHandler}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := limiter.AllowN(r.Context(), keyFn(r), limit, 1)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			setHeaders(w, result)
+			if result.Allowed == 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+				cfg.deniedHandler(w, r, result)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func setHeaders(w http.ResponseWriter, result *surgelimit.Result) {
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit.Rate))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(int(result.ResetAfter.Seconds())))
+}
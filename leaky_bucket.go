@@ -3,7 +3,10 @@ package surgelimit
 import (
 	"context"
 	"github.com/redis/go-redis/v9"
+	"math"
 	"strconv"
+	"sync"
+	"time"
 )
 
 // allowN is a Redis Lua script used for handling rate limiting based on the number of requests.
@@ -173,7 +176,18 @@ return {
 //   - Options: Configuration options for the LeakyBucketLimiter
 type LeakyBucketLimiter struct {
 	client  redis.UniversalClient
+	store   Store
 	Options Options
+
+	// LimitProvider, if set, is consulted by AllowAll to resolve the tiers
+	// to enforce for a key at call time instead of a single static Limit.
+	LimitProvider LimitProvider
+
+	// storeMu serializes the GET-compute-SET cycle used by the Store-backed
+	// path, since unlike the Lua scripts that make this atomic in Redis,
+	// Store only exposes separate Get/Set/Del calls. A single mutex is fine
+	// for the test/single-node/fallback use cases Store targets.
+	storeMu sync.Mutex
 }
 
 // NewLeakyBucketLimiter creates and returns a new LeakyBucketLimiter instance using the provided
@@ -206,6 +220,18 @@ func NewLeakyBucketLimiter(client redis.UniversalClient, options Options) *Leaky
 	}
 }
 
+// NewLeakyBucketLimiterStore creates and returns a new LeakyBucketLimiter
+// backed by store instead of Redis. This runs the same GCRA semantics as the
+// Redis-backed limiter, computed in Go against Store's Get/Set/Del rather
+// than in a Lua script, so it works with any Store implementation - such as
+// memory.NewStore for single-node deployments or tests.
+func NewLeakyBucketLimiterStore(store Store, options Options) *LeakyBucketLimiter {
+	return &LeakyBucketLimiter{
+		store:   store,
+		Options: options,
+	}
+}
+
 // Allow attempts to allow a single request for a given key under a rate-limiting
 // scheme defined by the `surgelimit.Limit` struct. This is a convenience method that
 // calls `AllowN` with `n` set to 1, meaning it checks if just one request can
@@ -283,6 +309,10 @@ func (l *LeakyBucketLimiter) AllowN(
 	limit Limit,
 	n int,
 ) (*Result, error) {
+	if l.store != nil {
+		return l.allowNStore(ctx, key, limit, n)
+	}
+
 	values := []interface{}{limit.Burst, limit.Rate, limit.Period.Seconds(), n}
 	v, err := allowN.Run(ctx, l.client, []string{keyWithPrefix(l.Options.KeyPrefix, key)}, values...).Result()
 	if err != nil {
@@ -355,6 +385,10 @@ func (l *LeakyBucketLimiter) AllowAtMost(
 	limit Limit,
 	n int,
 ) (*Result, error) {
+	if l.store != nil {
+		return l.allowAtMostStore(ctx, key, limit, n)
+	}
+
 	values := []interface{}{limit.Burst, limit.Rate, limit.Period.Seconds(), n}
 	v, err := allowAtMost.Run(ctx, l.client, []string{keyWithPrefix(l.Options.KeyPrefix, key)}, values...).Result()
 	if err != nil {
@@ -408,5 +442,122 @@ func (l *LeakyBucketLimiter) AllowAtMost(
 //		log.Printf("Warning: Failed to reset rate limiter for user_1234: %v", err)
 //	}
 func (l *LeakyBucketLimiter) Reset(ctx context.Context, key string) error {
+	if l.store != nil {
+		return l.store.Del(ctx, keyWithPrefix(l.Options.KeyPrefix, key))
+	}
 	return l.client.Del(ctx, keyWithPrefix(l.Options.KeyPrefix, key)).Err()
 }
+
+// allowNStore is the Store-backed equivalent of the allowN Lua script,
+// reproducing the same GCRA arithmetic in Go.
+func (l *LeakyBucketLimiter) allowNStore(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	l.storeMu.Lock()
+	defer l.storeMu.Unlock()
+
+	storeKey := keyWithPrefix(l.Options.KeyPrefix, key)
+	now := nowSeconds()
+	emissionInterval := limit.Period.Seconds() / float64(limit.Rate)
+	burstOffset := emissionInterval * float64(limit.Burst)
+	increment := emissionInterval * float64(n)
+
+	tat, ok, err := l.store.Get(ctx, storeKey)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		tat = now
+	}
+	tat = math.Max(tat, now)
+
+	newTat := tat + increment
+	diff := now - (newTat - burstOffset)
+	remaining := diff / emissionInterval
+
+	if remaining < 0 {
+		return &Result{
+			Limit:      limit,
+			Allowed:    0,
+			Remaining:  0,
+			RetryAfter: dur(-diff),
+			ResetAfter: dur(tat - now),
+		}, nil
+	}
+
+	resetAfter := newTat - now
+	if resetAfter > 0 {
+		if err := l.store.Set(ctx, storeKey, newTat, time.Duration(math.Ceil(resetAfter*float64(time.Second)))); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Result{
+		Limit:      limit,
+		Allowed:    n,
+		Remaining:  int(remaining),
+		RetryAfter: dur(-1),
+		ResetAfter: dur(resetAfter),
+	}, nil
+}
+
+// allowAtMostStore is the Store-backed equivalent of the allowAtMost Lua
+// script, reproducing the same GCRA arithmetic in Go.
+func (l *LeakyBucketLimiter) allowAtMostStore(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	l.storeMu.Lock()
+	defer l.storeMu.Unlock()
+
+	storeKey := keyWithPrefix(l.Options.KeyPrefix, key)
+	now := nowSeconds()
+	emissionInterval := limit.Period.Seconds() / float64(limit.Rate)
+	burstOffset := emissionInterval * float64(limit.Burst)
+
+	tat, ok, err := l.store.Get(ctx, storeKey)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		tat = now
+	}
+	tat = math.Max(tat, now)
+
+	diff := now - (tat - burstOffset)
+	remaining := diff / emissionInterval
+
+	if remaining < 1 {
+		return &Result{
+			Limit:      limit,
+			Allowed:    0,
+			Remaining:  0,
+			RetryAfter: dur(emissionInterval - diff),
+			ResetAfter: dur(tat - now),
+		}, nil
+	}
+
+	allowed := n
+	if remaining < float64(n) {
+		allowed = int(remaining)
+		remaining = 0
+	} else {
+		remaining -= float64(allowed)
+	}
+
+	increment := emissionInterval * float64(allowed)
+	newTat := tat + increment
+	resetAfter := newTat - now
+	if resetAfter > 0 {
+		if err := l.store.Set(ctx, storeKey, newTat, time.Duration(math.Ceil(resetAfter*float64(time.Second)))); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Result{
+		Limit:      limit,
+		Allowed:    allowed,
+		Remaining:  int(remaining),
+		RetryAfter: dur(-1),
+		ResetAfter: dur(resetAfter),
+	}, nil
+}
+
+func nowSeconds() float64 {
+	return float64(time.Now().UnixNano()) / 1e9
+}
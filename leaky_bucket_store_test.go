@@ -0,0 +1,78 @@
+package surgelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/driftdev/memory"
+)
+
+func TestLeakyBucketLimiterStoreAllowNConsumesBurstThenDenies(t *testing.T) {
+	store := memory.NewStore(time.Hour)
+	defer store.Close()
+	l := NewLeakyBucketLimiterStore(store, DefaultOptions())
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 2, Period: time.Second}
+
+	for i := 0; i < 2; i++ {
+		res, err := l.AllowN(ctx, "k", limit, 1)
+		if err != nil {
+			t.Fatalf("AllowN #%d: %v", i, err)
+		}
+		if res.Allowed != 1 {
+			t.Fatalf("AllowN #%d: Allowed = %d, want 1", i, res.Allowed)
+		}
+	}
+
+	res, err := l.AllowN(ctx, "k", limit, 1)
+	if err != nil {
+		t.Fatalf("AllowN over burst: %v", err)
+	}
+	if res.Allowed != 0 || res.Remaining != 0 {
+		t.Fatalf("AllowN over burst = Allowed=%d Remaining=%d, want 0 0", res.Allowed, res.Remaining)
+	}
+}
+
+func TestLeakyBucketLimiterStoreAllowAtMostPartiallyFills(t *testing.T) {
+	store := memory.NewStore(time.Hour)
+	defer store.Close()
+	l := NewLeakyBucketLimiterStore(store, DefaultOptions())
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 2, Period: time.Second}
+
+	res, err := l.AllowAtMost(ctx, "k", limit, 5)
+	if err != nil {
+		t.Fatalf("AllowAtMost: %v", err)
+	}
+	if res.Allowed != 2 {
+		t.Fatalf("AllowAtMost = Allowed %d, want 2 (capped at burst)", res.Allowed)
+	}
+}
+
+func TestLeakyBucketLimiterStoreResetClearsState(t *testing.T) {
+	store := memory.NewStore(time.Hour)
+	defer store.Close()
+	l := NewLeakyBucketLimiterStore(store, DefaultOptions())
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 1, Period: time.Hour}
+
+	if _, err := l.AllowN(ctx, "k", limit, 1); err != nil {
+		t.Fatalf("AllowN: %v", err)
+	}
+	if res, _ := l.AllowN(ctx, "k", limit, 1); res.Allowed != 0 {
+		t.Fatalf("expected burst to be exhausted before Reset")
+	}
+
+	if err := l.Reset(ctx, "k"); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+
+	res, err := l.AllowN(ctx, "k", limit, 1)
+	if err != nil {
+		t.Fatalf("AllowN after Reset: %v", err)
+	}
+	if res.Allowed != 1 {
+		t.Fatalf("AllowN after Reset: Allowed = %d, want 1", res.Allowed)
+	}
+}
@@ -0,0 +1,105 @@
+package leakybucket
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultKeyPrefix = "RATE_LIMIT"
+
+// Options holds the configuration settings for the Limiter.
+type Options struct {
+	// KeyPrefix is the prefix added to all keys used by the limiter.
+	KeyPrefix string
+}
+
+// DefaultOptions returns an Options struct populated with the default
+// settings for the Limiter.
+func DefaultOptions() Options {
+	return Options{
+		KeyPrefix: defaultKeyPrefix,
+	}
+}
+
+// Limiter enforces a Limit against a key using whichever Strategy the Limit
+// selects (GCRA, FixedWindow, or SlidingWindow). All three strategies return
+// the same Result shape, so callers can switch strategies per Limit without
+// changing how they interpret the outcome.
+type Limiter struct {
+	client  redis.UniversalClient
+	Options Options
+}
+
+// NewLimiter creates and returns a new Limiter instance using the provided
+// Redis client and options.
+func NewLimiter(client redis.UniversalClient, options Options) *Limiter {
+	return &Limiter{
+		client:  client,
+		Options: options,
+	}
+}
+
+// Allow attempts to allow a single request for key under limit. This is a
+// convenience method that calls AllowN with n set to 1.
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return l.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN attempts to allow n requests for key under limit, dispatching to
+// the Lua script for limit.Strategy.
+func (l *Limiter) AllowN(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	script := scriptFor(limit.Strategy)
+	values := []interface{}{limit.Burst, limit.Rate, limit.Period.Seconds(), n}
+	if script != allowN {
+		// fixedWindow and slidingWindow take burst, period and cost only.
+		values = []interface{}{limit.Burst, limit.Period.Seconds(), n}
+	}
+	v, err := script.Run(ctx, l.client, []string{keyWithPrefix(l.Options.KeyPrefix, key)}, values...).Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseResult(v, limit)
+}
+
+// scriptFor returns the Lua script implementing strategy. GCRA is the
+// default for any unrecognized strategy value.
+func scriptFor(strategy Strategy) *redis.Script {
+	switch strategy {
+	case FixedWindow:
+		return fixedWindow
+	case SlidingWindow:
+		return slidingWindow
+	default:
+		return allowN
+	}
+}
+
+func parseResult(v interface{}, limit Limit) (*Result, error) {
+	values := v.([]interface{})
+
+	retryAfter, err := strconv.ParseFloat(values[2].(string), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	resetAfter, err := strconv.ParseFloat(values[3].(string), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Limit:      limit,
+		Allowed:    int(values[0].(int64)),
+		Remaining:  int(values[1].(int64)),
+		RetryAfter: dur(retryAfter),
+		ResetAfter: dur(resetAfter),
+	}, nil
+}
+
+// Reset deletes key itself, which holds the GCRA and FixedWindow state. The
+// per-window counters SlidingWindow keeps under key:<window> are not removed.
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	return l.client.Del(ctx, keyWithPrefix(l.Options.KeyPrefix, key)).Err()
+}
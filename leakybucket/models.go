@@ -5,6 +5,24 @@ import (
 	"time"
 )
 
+// Strategy selects the algorithm used to enforce a Limit.
+type Strategy int
+
+const (
+	// GCRA enforces the limit using the Generic Cell Rate Algorithm, the
+	// default strategy. It smooths bursts evenly across the period.
+	GCRA Strategy = iota
+	// FixedWindow enforces the limit by counting requests in a window that
+	// starts with the first request and resets Period later; windows are not
+	// aligned to calendar boundaries. It is cheaper than GCRA but allows up
+	// to 2x burst at window boundaries.
+	FixedWindow
+	// SlidingWindow approximates a true sliding window by interpolating
+	// between the previous and current fixed windows, avoiding the
+	// boundary-burst problem of FixedWindow at a similar storage cost.
+	SlidingWindow
+)
+
 // Limit represents the configuration for rate limiting. It includes the rate (number of allowed
 // requests), burst (maximum number of requests allowed in a burst), and the period (duration for
 // which the rate limit applies).
@@ -15,6 +33,9 @@ type Limit struct {
 	Burst int
 	// Period specifies the duration for which the rate limit is applied (e.g., 1 minute, 1 hour).
 	Period time.Duration
+	// Strategy selects the algorithm used to enforce this Limit. The zero
+	// value is GCRA.
+	Strategy Strategy
 }
 
 // String returns a string representation of the Limit, showing the rate, period, and burst capacity.
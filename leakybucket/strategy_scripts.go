@@ -0,0 +1,85 @@
+package leakybucket
+
+import "github.com/redis/go-redis/v9"
+
+// fixedWindow is a Redis Lua script implementing the fixed-window counting
+// strategy: INCRBY the window counter by cost, EXPIRE it on first use, and
+// deny (rolling back the increment) once the counter would exceed burst.
+// This gives "N requests per window" semantics that GCRA only approximates,
+// at the cost of allowing up to 2x burst at window boundaries. A window
+// starts with the first request after the previous counter expired and
+// lasts period seconds; it is not aligned to calendar boundaries.
+//
+// Parameters used in the script:
+//   - KEYS[1]: The counter key for the current window.
+//   - ARGV[1]: The burst size (maximum requests per window).
+//   - ARGV[2]: The period (window length, in seconds).
+//   - ARGV[3]: The cost (number of requests being attempted).
+var fixedWindow = redis.NewScript(`
+local window_key = KEYS[1]
+local burst = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+
+local count = redis.call("INCRBY", window_key, cost)
+if count == cost then
+  redis.call("EXPIRE", window_key, period)
+end
+
+local ttl = redis.call("TTL", window_key)
+if ttl < 0 then
+  ttl = period
+  redis.call("EXPIRE", window_key, period)
+end
+
+if count > burst then
+  redis.call("DECRBY", window_key, cost)
+  return {0, 0, tostring(ttl), tostring(ttl)}
+end
+
+return {cost, burst - count, tostring(-1), tostring(ttl)}
+`)
+
+// slidingWindow is a Redis Lua script implementing the Cloudflare-style
+// sliding window strategy: it keeps a counter for the current and previous
+// fixed windows and interpolates between them by how far into the current
+// window `now` falls, avoiding the burst-at-boundary problem of a plain
+// fixed window.
+//
+// Parameters used in the script:
+//   - KEYS[1]: The base key; the current and previous window counters are
+//     derived from it by appending their window index.
+//   - ARGV[1]: The burst size (maximum requests per period).
+//   - ARGV[2]: The period (window length, in seconds).
+//   - ARGV[3]: The cost (number of requests being attempted).
+var slidingWindow = redis.NewScript(`
+local base_key = KEYS[1]
+local burst = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+
+local now = redis.call("TIME")
+now = now[1] + (now[2] / 1000000)
+
+local window = math.floor(now / period)
+local curr_key = base_key .. ":" .. window
+local prev_key = base_key .. ":" .. (window - 1)
+
+local elapsed = now - (window * period)
+local weight = 1 - (elapsed / period)
+
+local prev = tonumber(redis.call("GET", prev_key) or "0")
+local curr = tonumber(redis.call("GET", curr_key) or "0")
+local count = prev * weight + curr
+
+local reset_after = period - elapsed
+
+if count + cost > burst then
+  return {0, 0, tostring(reset_after), tostring(reset_after)}
+end
+
+redis.call("INCRBY", curr_key, cost)
+redis.call("EXPIRE", curr_key, period * 2)
+
+return {cost, burst - (count + cost), tostring(-1), tostring(reset_after)}
+`)
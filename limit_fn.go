@@ -0,0 +1,52 @@
+package surgelimit
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LimitFn resolves the effective Limit for a key at call time, instead of
+// requiring callers to pass a fixed Limit to every Allow call. This enables
+// tiered plans (free/pro/enterprise), per-tenant overrides loaded from a
+// config store, or time-of-day limits without threading a Limit through
+// every call site.
+type LimitFn func(ctx context.Context, key string) (Limit, error)
+
+// FnLimiter wraps a Limiter with a LimitFn, resolving the Limit to enforce
+// for each key at call time rather than accepting it as an argument.
+type FnLimiter struct {
+	limiter *Limiter
+	fn      LimitFn
+}
+
+// NewLimiterFn creates a FnLimiter that enforces whatever Limit fn resolves
+// for a given key.
+func NewLimiterFn(client redis.UniversalClient, fn LimitFn, options Options) *FnLimiter {
+	return &FnLimiter{limiter: NewLimiter(client, options), fn: fn}
+}
+
+// AllowFn resolves the Limit for key via the configured LimitFn and then
+// allows a single request against it.
+func (f *FnLimiter) AllowFn(ctx context.Context, key string) (*Result, error) {
+	return f.AllowFnN(ctx, key, 1)
+}
+
+// AllowFnN is like AllowFn but attempts to allow n requests.
+func (f *FnLimiter) AllowFnN(ctx context.Context, key string, n int) (*Result, error) {
+	limit, err := f.fn(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return f.limiter.AllowN(ctx, key, limit, n)
+}
+
+// AllowAtMostFn resolves the Limit for key via the configured LimitFn and
+// then allows up to n requests against it.
+func (f *FnLimiter) AllowAtMostFn(ctx context.Context, key string, n int) (*Result, error) {
+	limit, err := f.fn(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return f.limiter.AllowAtMost(ctx, key, limit, n)
+}
@@ -0,0 +1,108 @@
+package surgelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrNoLimitProvider is returned by AllowAll when the LeakyBucketLimiter's
+// LimitProvider field hasn't been set.
+var ErrNoLimitProvider = errors.New("surgelimit: AllowAll requires LimitProvider to be set")
+
+// LimitProvider resolves the set of Limits to enforce for a key at call
+// time, e.g. loading a caller's plan-specific tiered quotas ("10/s AND
+// 100/min AND 1000/h") from Redis or a config store instead of hardcoding
+// them.
+type LimitProvider interface {
+	LimitFor(ctx context.Context, key string) ([]Limit, error)
+}
+
+// AllowAll resolves key's tiers via provider and enforces all of them
+// atomically in a single round trip, the same way AllowMany does for a
+// static slice of limits: the request is only counted against every tier if
+// all of them currently allow it, so partial commits (which calling AllowN
+// once per tier cannot avoid) never happen.
+//
+// The merged Result reports the most restrictive outcome across tiers:
+// denied if any tier denied, with the largest RetryAfter among the denying
+// tiers; allowed with the smallest Remaining otherwise.
+func (l *LeakyBucketLimiter) AllowAll(ctx context.Context, key string) (*Result, error) {
+	if l.LimitProvider == nil {
+		return nil, ErrNoLimitProvider
+	}
+
+	limits, err := l.LimitProvider.LimitFor(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	results, err := l.allowManyTiers(ctx, key, limits, 1)
+	if err != nil {
+		return nil, err
+	}
+	return mostRestrictiveResult(results), nil
+}
+
+// allowManyTiers runs the same atomic multi-key commit as AllowMany/AllowManyN,
+// but against the LeakyBucketLimiter's client rather than a Limiter's.
+func (l *LeakyBucketLimiter) allowManyTiers(ctx context.Context, key string, limits []Limit, n int) ([]*Result, error) {
+	keys := make([]string, len(limits))
+	values := make([]interface{}, 0, 1+len(limits)*4)
+	values = append(values, len(limits))
+	for i, limit := range limits {
+		keys[i] = keyWithPrefix(l.Options.KeyPrefix, fmt.Sprintf("%s:%d", key, i))
+		values = append(values, limit.Burst, limit.Rate, limit.Period.Seconds(), n)
+	}
+
+	v, err := allowMany.Run(ctx, l.client, keys, values...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := v.([]interface{})
+	results := make([]*Result, len(limits))
+	for i, row := range rows {
+		fields := row.([]interface{})
+
+		retryAfter, err := strconv.ParseFloat(fields[2].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+		resetAfter, err := strconv.ParseFloat(fields[3].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+
+		results[i] = &Result{
+			Limit:      limits[i],
+			Allowed:    int(fields[0].(int64)),
+			Remaining:  int(fields[1].(int64)),
+			RetryAfter: dur(retryAfter),
+			ResetAfter: dur(resetAfter),
+		}
+	}
+	return results, nil
+}
+
+// mostRestrictiveResult collapses per-tier results into the single Result
+// AllowAll reports: denied beats allowed, the largest RetryAfter wins among
+// denied tiers, and the smallest Remaining wins among allowed ones.
+func mostRestrictiveResult(results []*Result) *Result {
+	most := results[0]
+	for _, r := range results[1:] {
+		switch {
+		case most.Allowed > 0 && r.Allowed == 0:
+			most = r
+		case most.Allowed == 0 && r.Allowed == 0 && r.RetryAfter > most.RetryAfter:
+			most = r
+		case most.Allowed > 0 && r.Allowed > 0 && r.Remaining < most.Remaining:
+			most = r
+		}
+	}
+	return most
+}
@@ -0,0 +1,117 @@
+// Package memory implements surgelimit.Store entirely in process, for
+// deterministic unit tests, single-node deployments that don't want a Redis
+// dependency, and as a fallback while Redis is unavailable.
+package memory
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/driftdev/surgelimit"
+)
+
+const shardCount = 32
+
+var _ surgelimit.Store = (*Store)(nil)
+
+// Store is a sharded, in-memory implementation of surgelimit.Store. Each of
+// its shards is guarded by its own mutex, and a background janitor evicts
+// entries once their TTL has elapsed.
+type Store struct {
+	shards [shardCount]*shard
+	done   chan struct{}
+}
+
+type shard struct {
+	mu      sync.Mutex
+	tats    map[string]float64
+	expires map[string]time.Time
+}
+
+// NewStore creates a Store and starts its janitor, which sweeps expired
+// entries every janitorInterval.
+func NewStore(janitorInterval time.Duration) *Store {
+	s := &Store{done: make(chan struct{})}
+	for i := range s.shards {
+		s.shards[i] = &shard{
+			tats:    make(map[string]float64),
+			expires: make(map[string]time.Time),
+		}
+	}
+
+	go s.janitor(janitorInterval)
+	return s
+}
+
+// Close stops the background janitor.
+func (s *Store) Close() {
+	close(s.done)
+}
+
+func (s *Store) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			now := time.Now()
+			for _, sh := range s.shards {
+				sh.mu.Lock()
+				for key, expiresAt := range sh.expires {
+					if now.After(expiresAt) {
+						delete(sh.tats, key)
+						delete(sh.expires, key)
+					}
+				}
+				sh.mu.Unlock()
+			}
+		}
+	}
+}
+
+func (s *Store) shardFor(key string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return s.shards[h.Sum32()%shardCount]
+}
+
+// Get returns the TAT stored for key and whether it was found. An entry
+// whose TTL has already elapsed is treated as not found.
+func (s *Store) Get(ctx context.Context, key string) (float64, bool, error) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	expiresAt, ok := sh.expires[key]
+	if !ok || time.Now().After(expiresAt) {
+		return 0, false, nil
+	}
+	return sh.tats[key], true, nil
+}
+
+// Set stores tat for key, expiring it after ttl.
+func (s *Store) Set(ctx context.Context, key string, tat float64, ttl time.Duration) error {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	sh.tats[key] = tat
+	sh.expires[key] = time.Now().Add(ttl)
+	return nil
+}
+
+// Del removes any stored state for key.
+func (s *Store) Del(ctx context.Context, key string) error {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	delete(sh.tats, key)
+	delete(sh.expires, key)
+	return nil
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStoreGetSetDel(t *testing.T) {
+	s := NewStore(time.Hour)
+	defer s.Close()
+	ctx := context.Background()
+
+	if _, ok, err := s.Get(ctx, "missing"); err != nil || ok {
+		t.Fatalf("Get(missing) = ok=%v err=%v, want ok=false err=nil", ok, err)
+	}
+
+	if err := s.Set(ctx, "k", 42, time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	tat, ok, err := s.Get(ctx, "k")
+	if err != nil || !ok || tat != 42 {
+		t.Fatalf("Get(k) = tat=%v ok=%v err=%v, want 42 true nil", tat, ok, err)
+	}
+
+	if err := s.Del(ctx, "k"); err != nil {
+		t.Fatalf("Del: %v", err)
+	}
+
+	if _, ok, err := s.Get(ctx, "k"); err != nil || ok {
+		t.Fatalf("Get(k) after Del = ok=%v err=%v, want ok=false err=nil", ok, err)
+	}
+}
+
+func TestStoreGetExpiresAfterTTL(t *testing.T) {
+	s := NewStore(time.Hour)
+	defer s.Close()
+	ctx := context.Background()
+
+	if err := s.Set(ctx, "k", 1, 10*time.Millisecond); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok, err := s.Get(ctx, "k"); err != nil || ok {
+		t.Fatalf("Get(k) after TTL = ok=%v err=%v, want ok=false err=nil (Get must treat an elapsed TTL as not found even before the janitor sweeps it)", ok, err)
+	}
+}
+
+func TestStoreJanitorEvictsExpiredEntries(t *testing.T) {
+	s := NewStore(5 * time.Millisecond)
+	defer s.Close()
+	ctx := context.Background()
+
+	if err := s.Set(ctx, "k", 1, 5*time.Millisecond); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	sh := s.shardFor("k")
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		sh.mu.Lock()
+		_, present := sh.tats["k"]
+		sh.mu.Unlock()
+		if !present {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("janitor never evicted the expired entry")
+}
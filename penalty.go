@@ -0,0 +1,153 @@
+package surgelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// penalize is a Redis Lua script that unconditionally consumes `cost` tokens
+// from a key's bucket, even when cost exceeds the available burst. This
+// pushes the bucket's TAT into the future beyond what a normal request would
+// allow, extending the caller's lockout as a quarantine.
+//
+// Parameters used in the script:
+//   - KEYS[1]: The key used for rate limiting in Redis.
+//   - ARGV[1]: The burst size.
+//   - ARGV[2]: The rate (number of requests allowed per period).
+//   - ARGV[3]: The period (duration in seconds for the rate limit).
+//   - ARGV[4]: The cost (number of tokens to consume, may exceed burst).
+var penalize = redis.NewScript(`
+redis.replicate_commands()
+
+local rate_limit_key = KEYS[1]
+local rate = ARGV[2]
+local period = ARGV[3]
+local cost = tonumber(ARGV[4])
+
+local emission_interval = period / rate
+local increment = emission_interval * cost
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local tat = redis.call("GET", rate_limit_key)
+if not tat then
+  tat = now
+else
+  tat = tonumber(tat)
+end
+tat = math.max(tat, now)
+
+local new_tat = tat + increment
+local reset_after = new_tat - now
+redis.call("SET", rate_limit_key, new_tat, "EX", math.ceil(reset_after))
+
+return tostring(reset_after)
+`)
+
+// peekSrc is the body of a Lua script that reports the current state of a
+// key's bucket without mutating it. It is run via EVAL_RO (rather than
+// wrapped in a redis.Script, which only issues read-write EVAL/EVALSHA) so
+// it can be served from a read replica.
+//
+// Parameters used in the script:
+//   - KEYS[1]: The key used for rate limiting in Redis.
+//   - ARGV[1]: The burst size.
+//   - ARGV[2]: The rate (number of requests allowed per period).
+//   - ARGV[3]: The period (duration in seconds for the rate limit).
+const peekSrc = `
+local rate_limit_key = KEYS[1]
+local burst = ARGV[1]
+local rate = ARGV[2]
+local period = ARGV[3]
+
+local emission_interval = period / rate
+local burst_offset = emission_interval * burst
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local tat = redis.call("GET", rate_limit_key)
+if not tat then
+  tat = now
+else
+  tat = tonumber(tat)
+end
+tat = math.max(tat, now)
+
+local diff = now - (tat - burst_offset)
+local remaining = diff / emission_interval
+if remaining < 0 then
+  remaining = 0
+end
+
+return {remaining, tostring(tat - now)}
+`
+
+// Penalize consumes `cost` tokens from key's bucket unconditionally, even
+// when cost exceeds limit.Burst. This is intended for punishing abusive
+// callers (e.g. after repeated auth failures or WAF hits) by extending their
+// lockout well beyond what a normal denied request would produce, without a
+// separate storage layer to track penalties.
+//
+// Returns the resulting ResetAfter: how long until the bucket has fully
+// drained. A single request is allowed again (Burst-1) emission intervals
+// (Period/Rate) before that.
+func (l *Limiter) Penalize(ctx context.Context, key string, limit Limit, cost int) (time.Duration, error) {
+	values := []interface{}{limit.Burst, limit.Rate, limit.Period.Seconds(), cost}
+	v, err := penalize.Run(ctx, l.client, []string{keyWithPrefix(l.Options.KeyPrefix, key)}, values...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	resetAfter, err := strconv.ParseFloat(v.(string), 64)
+	if err != nil {
+		return 0, err
+	}
+	return dur(resetAfter), nil
+}
+
+// SetTAT administratively quarantines key by storing until as its TAT,
+// regardless of its current bucket state. A request for n against a Limit
+// is then denied while until is more than (Burst-n) emission intervals
+// (Period/Rate) away, so with a Burst of 1 every request is denied until
+// that time elapses.
+func (l *Limiter) SetTAT(ctx context.Context, key string, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return l.Reset(ctx, key)
+	}
+
+	tat := float64(until.Unix()) - 1483228800 + float64(until.Nanosecond())/1e9
+	return l.client.Set(ctx, keyWithPrefix(l.Options.KeyPrefix, key), tat, ttl).Err()
+}
+
+// Peek reports the current state of key's bucket without consuming any
+// tokens or mutating any state. It is served via EVAL_RO so it can be routed
+// to a read replica.
+func (l *Limiter) Peek(ctx context.Context, key string, limit Limit) (*Result, error) {
+	values := []interface{}{limit.Burst, limit.Rate, limit.Period.Seconds()}
+	v, err := l.client.EvalRO(ctx, peekSrc, []string{keyWithPrefix(l.Options.KeyPrefix, key)}, values...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := v.([]interface{})
+	resetAfter, err := strconv.ParseFloat(fields[1].(string), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Limit:      limit,
+		Allowed:    0,
+		Remaining:  int(fields[0].(int64)),
+		RetryAfter: -1,
+		ResetAfter: dur(resetAfter),
+	}, nil
+}
@@ -0,0 +1,65 @@
+package zenlimit
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// setLimit is a Redis Lua script that rescales a key's stored TAT from the
+// emission interval implied by the old limit to the one implied by the new
+// limit, so an in-flight bucket keeps its relative "debt" instead of being
+// reset to empty (which would let an abuser refill instantly) or left
+// interpreted under the wrong rate (which would misreport remaining/retry).
+//
+// Parameters used in the script:
+//   - KEYS[1]: The key used for rate limiting in Redis.
+//   - ARGV[1]: The old period (duration in seconds) used to derive the old emission interval.
+//   - ARGV[2]: The old rate.
+//   - ARGV[3]: The new period.
+//   - ARGV[4]: The new rate.
+var setLimit = redis.NewScript(`
+redis.replicate_commands()
+
+local rate_limit_key = KEYS[1]
+local old_period = tonumber(ARGV[1])
+local old_rate = tonumber(ARGV[2])
+local new_period = tonumber(ARGV[3])
+local new_rate = tonumber(ARGV[4])
+
+local old_emission_interval = old_period / old_rate
+local new_emission_interval = new_period / new_rate
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local tat = redis.call("GET", rate_limit_key)
+if not tat then
+  return 1
+end
+tat = tonumber(tat)
+
+local slack = tat - now
+if slack <= 0 then
+  return 1
+end
+
+local new_tat = now + slack * (new_emission_interval / old_emission_interval)
+redis.call("SET", rate_limit_key, new_tat, "EX", math.ceil(new_tat - now))
+return 1
+`)
+
+// SetLimit changes the Rate/Burst/Period enforced for key without resetting
+// its in-flight bucket state: the stored TAT is rescaled proportionally
+// from the emission interval implied by oldLimit to the one implied by
+// newLimit, so a caller who was partway through their old quota keeps the
+// same relative standing under the new one. A plain Reset would instead let
+// an abuser refill their bucket instantly.
+func (l *Limiter) SetLimit(ctx context.Context, key string, oldLimit, newLimit Limit) error {
+	values := []interface{}{
+		oldLimit.Period.Seconds(), oldLimit.Rate,
+		newLimit.Period.Seconds(), newLimit.Rate,
+	}
+	return setLimit.Run(ctx, l.client, []string{keyWithPrefix(l.Options.KeyPrefix, key)}, values...).Err()
+}
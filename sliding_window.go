@@ -0,0 +1,123 @@
+package surgelimit
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowAllowN is a Redis Lua script implementing the sliding-window-log
+// algorithm: every allowed request's timestamp is recorded as a member of a
+// Redis ZSET, scored by the time it was made. This gives an exact "N per
+// rolling period" guarantee, unlike GCRA or fixed-window, at the cost of
+// storing one ZSET member per request within the window.
+//
+// The script performs the following operations:
+//   - Evicts members older than now-period from the ZSET.
+//   - Counts the remaining members and denies the request if count+cost would exceed limit.
+//   - Otherwise adds cost new members scored at now and refreshes the key's TTL.
+//
+// Parameters used in the script:
+//   - KEYS[1]: The ZSET key used for rate limiting in Redis.
+//   - ARGV[1]: The limit (maximum number of requests allowed within period).
+//   - ARGV[2]: The period, in seconds, over which limit applies.
+//   - ARGV[3]: The cost (number of requests being attempted).
+var slidingWindowAllowN = redis.NewScript(`
+redis.replicate_commands()
+
+local window_key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+
+local now = redis.call("TIME")
+now = (now[1]) + (now[2] / 1000000)
+
+redis.call("ZREMRANGEBYSCORE", window_key, "-inf", now - period)
+
+local count = redis.call("ZCARD", window_key)
+
+if count + cost > limit then
+  local retry_after = period
+  local oldest = redis.call("ZRANGE", window_key, 0, 0, "WITHSCORES")
+  if oldest[2] then
+    retry_after = tonumber(oldest[2]) + period - now
+  end
+  return {
+    0, -- allowed
+    math.max(0, limit - count), -- remaining
+    tostring(retry_after),
+    tostring(period),
+  }
+end
+
+for i = 1, cost do
+  redis.call("ZADD", window_key, now, now .. "-" .. i .. "-" .. math.random())
+end
+redis.call("PEXPIRE", window_key, period * 1000)
+
+return {cost, limit - count - cost, tostring(-1), tostring(period)}
+`)
+
+// SlidingWindowLimiter is a sibling of LeakyBucketLimiter exposing exact
+// "N per rolling period" semantics via a sliding-window log, rather than the
+// smoothed or bursty approximations of GCRA and token-bucket. It shares
+// Limit, Result, and Options, but Limit.Rate and Limit.Period are the only
+// fields consulted -
+// the window holds exactly Limit.Rate requests per Limit.Period, regardless
+// of Limit.Burst.
+type SlidingWindowLimiter struct {
+	client  redis.UniversalClient
+	Options Options
+}
+
+// NewSlidingWindowLimiter creates and returns a new SlidingWindowLimiter
+// using the provided Redis client and options.
+func NewSlidingWindowLimiter(client redis.UniversalClient, options Options) *SlidingWindowLimiter {
+	return &SlidingWindowLimiter{
+		client:  client,
+		Options: options,
+	}
+}
+
+// Allow attempts to allow a single request for key under limit. This is a
+// convenience method that calls AllowN with n set to 1.
+func (l *SlidingWindowLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return l.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN attempts to allow n requests for key under limit, denying the whole
+// request if fewer than n slots remain in the rolling window.
+func (l *SlidingWindowLimiter) AllowN(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	values := []interface{}{limit.Rate, limit.Period.Seconds(), n}
+	v, err := slidingWindowAllowN.Run(ctx, l.client, []string{keyWithPrefix(l.Options.KeyPrefix, key)}, values...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	results := v.([]interface{})
+
+	retryAfter, err := strconv.ParseFloat(results[2].(string), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	resetAfter, err := strconv.ParseFloat(results[3].(string), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Limit:      limit,
+		Allowed:    int(results[0].(int64)),
+		Remaining:  int(results[1].(int64)),
+		RetryAfter: dur(retryAfter),
+		ResetAfter: dur(resetAfter),
+	}, nil
+}
+
+// Reset clears the rolling window's state for key.
+func (l *SlidingWindowLimiter) Reset(ctx context.Context, key string) error {
+	return l.client.Del(ctx, keyWithPrefix(l.Options.KeyPrefix, key)).Err()
+}
@@ -0,0 +1,22 @@
+package surgelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Store abstracts the storage operations the allowN/allowAtMost Lua scripts
+// perform against Redis - GET the stored TAT (theoretical arrival time), SET
+// it back with a TTL, and DEL it - so a LeakyBucketLimiter can run its GCRA
+// logic against a backend other than Redis. This is primarily useful for
+// deterministic unit tests, single-node deployments that don't want a Redis
+// dependency, and as a fallback mode while Redis is unavailable.
+type Store interface {
+	// Get returns the TAT stored for key and whether it was found. A
+	// missing key is not an error; ok is false and err is nil.
+	Get(ctx context.Context, key string) (tat float64, ok bool, err error)
+	// Set stores tat for key, expiring it after ttl.
+	Set(ctx context.Context, key string, tat float64, ttl time.Duration) error
+	// Del removes any stored state for key.
+	Del(ctx context.Context, key string) error
+}
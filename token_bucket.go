@@ -0,0 +1,203 @@
+package surgelimit
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketAllowN is a Redis Lua script implementing classic token-bucket
+// rate limiting, as an alternative to the GCRA algorithm used by
+// LeakyBucketLimiter. Unlike GCRA, which smooths a burst out over the
+// emission interval, a token bucket lets a caller instantly drain an entire
+// burst the moment it refills.
+//
+// The script performs the following operations:
+//   - Retrieves the current time and the bucket's stored token count and last refill time.
+//   - Refills the bucket proportionally to the time elapsed since the last refill, capped at burst.
+//   - If the bucket holds at least `cost` tokens, subtracts them and allows the request.
+//   - Otherwise denies the request and reports how long until enough tokens accumulate.
+//
+// Parameters used in the script:
+//   - KEYS[1]: The key used for rate limiting in Redis.
+//   - ARGV[1]: The burst size (maximum number of tokens the bucket can hold).
+//   - ARGV[2]: The rate (number of tokens added per period).
+//   - ARGV[3]: The period (duration in seconds over which rate tokens are added).
+//   - ARGV[4]: The cost (number of tokens being requested).
+var tokenBucketAllowN = redis.NewScript(`
+redis.replicate_commands()
+
+local bucket_key = KEYS[1]
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local rate_per_sec = rate / period
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local data = redis.call("HMGET", bucket_key, "tokens", "ts")
+local tokens = tonumber(data[1])
+local last = tonumber(data[2])
+
+if tokens == nil then
+  tokens = burst
+  last = now
+end
+
+tokens = math.min(burst, tokens + (now - last) * rate_per_sec)
+local ttl = math.ceil(burst / rate_per_sec) + 1
+
+if tokens < cost then
+  local retry_after = (cost - tokens) / rate_per_sec
+  redis.call("HSET", bucket_key, "tokens", tokens, "ts", now)
+  redis.call("EXPIRE", bucket_key, ttl)
+  return {
+    0, -- allowed
+    math.floor(tokens), -- remaining
+    tostring(retry_after),
+    tostring((burst - tokens) / rate_per_sec),
+  }
+end
+
+tokens = tokens - cost
+redis.call("HSET", bucket_key, "tokens", tokens, "ts", now)
+redis.call("EXPIRE", bucket_key, ttl)
+return {cost, math.floor(tokens), tostring(-1), tostring((burst - tokens) / rate_per_sec)}
+`)
+
+// tokenBucketAllowAtMost is the AllowAtMost variant of tokenBucketAllowN: it
+// grants as many of the requested cost tokens as the bucket currently holds,
+// down to zero, instead of denying the whole request when it can't grant all
+// of cost.
+//
+// Parameters used in the script are the same as tokenBucketAllowN.
+var tokenBucketAllowAtMost = redis.NewScript(`
+redis.replicate_commands()
+
+local bucket_key = KEYS[1]
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local rate_per_sec = rate / period
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local data = redis.call("HMGET", bucket_key, "tokens", "ts")
+local tokens = tonumber(data[1])
+local last = tonumber(data[2])
+
+if tokens == nil then
+  tokens = burst
+  last = now
+end
+
+tokens = math.min(burst, tokens + (now - last) * rate_per_sec)
+local ttl = math.ceil(burst / rate_per_sec) + 1
+
+if tokens < 1 then
+  local retry_after = (1 - tokens) / rate_per_sec
+  redis.call("HSET", bucket_key, "tokens", tokens, "ts", now)
+  redis.call("EXPIRE", bucket_key, ttl)
+  return {
+    0, -- allowed
+    math.floor(tokens), -- remaining
+    tostring(retry_after),
+    tostring((burst - tokens) / rate_per_sec),
+  }
+end
+
+if tokens < cost then
+  cost = math.floor(tokens)
+end
+tokens = tokens - cost
+
+redis.call("HSET", bucket_key, "tokens", tokens, "ts", now)
+redis.call("EXPIRE", bucket_key, ttl)
+return {cost, math.floor(tokens), tostring(-1), tostring((burst - tokens) / rate_per_sec)}
+`)
+
+// TokenBucketLimiter is a sibling of LeakyBucketLimiter exposing the same
+// Allow/AllowN/AllowAtMost/Reset surface, backed by a classic token-bucket
+// instead of GCRA. It shares Limit, Result, and Options with
+// LeakyBucketLimiter, so the two are interchangeable: pick whichever burst
+// behavior a given route needs.
+type TokenBucketLimiter struct {
+	client  redis.UniversalClient
+	Options Options
+}
+
+// NewTokenBucketLimiter creates and returns a new TokenBucketLimiter instance
+// using the provided Redis client and options.
+func NewTokenBucketLimiter(client redis.UniversalClient, options Options) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		client:  client,
+		Options: options,
+	}
+}
+
+// Allow attempts to allow a single request for key under limit. This is a
+// convenience method that calls AllowN with n set to 1.
+func (l *TokenBucketLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return l.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN attempts to allow n requests for key under limit, refilling the
+// bucket proportionally to the time elapsed since it was last checked and
+// denying the request outright if it doesn't hold at least n tokens.
+func (l *TokenBucketLimiter) AllowN(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	values := []interface{}{limit.Burst, limit.Rate, limit.Period.Seconds(), n}
+	v, err := tokenBucketAllowN.Run(ctx, l.client, []string{keyWithPrefix(l.Options.KeyPrefix, key)}, values...).Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseTokenBucketResult(v, limit)
+}
+
+// AllowAtMost attempts to allow up to n requests for key under limit,
+// granting as many tokens as the bucket currently holds instead of denying
+// the whole request when it can't grant all of n.
+func (l *TokenBucketLimiter) AllowAtMost(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	values := []interface{}{limit.Burst, limit.Rate, limit.Period.Seconds(), n}
+	v, err := tokenBucketAllowAtMost.Run(ctx, l.client, []string{keyWithPrefix(l.Options.KeyPrefix, key)}, values...).Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseTokenBucketResult(v, limit)
+}
+
+// Reset clears the bucket's state for key, so it refills from full on the
+// next request.
+func (l *TokenBucketLimiter) Reset(ctx context.Context, key string) error {
+	return l.client.Del(ctx, keyWithPrefix(l.Options.KeyPrefix, key)).Err()
+}
+
+func parseTokenBucketResult(v interface{}, limit Limit) (*Result, error) {
+	values := v.([]interface{})
+
+	retryAfter, err := strconv.ParseFloat(values[2].(string), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	resetAfter, err := strconv.ParseFloat(values[3].(string), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Limit:      limit,
+		Allowed:    int(values[0].(int64)),
+		Remaining:  int(values[1].(int64)),
+		RetryAfter: dur(retryAfter),
+		ResetAfter: dur(resetAfter),
+	}, nil
+}
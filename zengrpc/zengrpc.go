@@ -0,0 +1,129 @@
+// Package zengrpc adapts a zenlimiter.Limiter to the shape of Envoy's v3
+// RateLimitService (envoy.service.ratelimit.v3), so superlimit-go can be
+// dropped in as a rate-limit service behind an Envoy or Istio sidecar.
+//
+// The types here mirror the fields of the generated
+// envoy/service/ratelimit/v3 protobuf messages (RateLimitRequest,
+// RateLimitResponse, RateLimitResponse_DescriptorStatus) closely enough
+// that wiring this package up to the real
+// envoy.service.ratelimit.v3.RateLimitServiceServer interface is a matter
+// of translating to/from the generated types at the gRPC boundary; the
+// decision logic itself lives in Service.ShouldRateLimit.
+package zengrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/driftdev/zenlimiter"
+)
+
+// Code mirrors envoy.service.ratelimit.v3.RateLimitResponse_Code.
+type Code int
+
+const (
+	// Unknown mirrors RateLimitResponse_UNKNOWN.
+	Unknown Code = iota
+	// OK mirrors RateLimitResponse_OK.
+	OK
+	// OverLimit mirrors RateLimitResponse_OVER_LIMIT.
+	OverLimit
+)
+
+// DescriptorEntry mirrors one envoy.extensions.common.ratelimit.v3.RateLimitDescriptor.Entry.
+type DescriptorEntry struct {
+	Key   string
+	Value string
+}
+
+// Descriptor mirrors one envoy.extensions.common.ratelimit.v3.RateLimitDescriptor.
+type Descriptor struct {
+	Entries []DescriptorEntry
+}
+
+// KeyBuilder maps a descriptor to the key used to enforce its rate limit.
+type KeyBuilder func(descriptor Descriptor) string
+
+// LimitResolver resolves the zenlimiter.Limit configured for a descriptor,
+// e.g. looked up from a config file keyed by the descriptor's entries.
+type LimitResolver func(descriptor Descriptor) (zenlimiter.Limit, error)
+
+// DescriptorStatus mirrors envoy.service.ratelimit.v3.RateLimitResponse_DescriptorStatus.
+type DescriptorStatus struct {
+	Code               Code
+	DurationUntilReset time.Duration
+	LimitRemaining     uint32
+}
+
+// Response mirrors envoy.service.ratelimit.v3.RateLimitResponse.
+type Response struct {
+	OverallCode Code
+	Statuses    []DescriptorStatus
+}
+
+// Request mirrors envoy.service.ratelimit.v3.RateLimitRequest.
+type Request struct {
+	Domain      string
+	Descriptors []Descriptor
+	HitsAddend  uint32
+}
+
+// Service implements the decision logic behind Envoy's RateLimitService
+// using a zenlimiter.Limiter, so any existing backend (Redis, EchoVault,
+// in-memory) can serve as the rate-limit store for a sidecar.
+type Service struct {
+	limiter       *zenlimiter.Limiter
+	keyBuilder    KeyBuilder
+	limitResolver LimitResolver
+}
+
+// NewService creates a Service that resolves each descriptor's key via
+// keyBuilder and its Limit via limitResolver.
+func NewService(limiter *zenlimiter.Limiter, keyBuilder KeyBuilder, limitResolver LimitResolver) *Service {
+	return &Service{
+		limiter:       limiter,
+		keyBuilder:    keyBuilder,
+		limitResolver: limitResolver,
+	}
+}
+
+// ShouldRateLimit evaluates every descriptor in req against its resolved
+// Limit and returns the combined Response, with OverallCode set to
+// OverLimit if any descriptor is denied.
+func (s *Service) ShouldRateLimit(ctx context.Context, req Request) (*Response, error) {
+	n := int(req.HitsAddend)
+	if n == 0 {
+		n = 1
+	}
+
+	resp := &Response{
+		OverallCode: OK,
+		Statuses:    make([]DescriptorStatus, len(req.Descriptors)),
+	}
+
+	for i, descriptor := range req.Descriptors {
+		limit, err := s.limitResolver(descriptor)
+		if err != nil {
+			return nil, err
+		}
+
+		key := s.keyBuilder(descriptor)
+		result, err := s.limiter.AllowN(ctx, key, limit, n)
+		if err != nil {
+			return nil, err
+		}
+
+		status := DescriptorStatus{
+			Code:               OK,
+			DurationUntilReset: result.ResetAfter,
+			LimitRemaining:     uint32(result.Remaining),
+		}
+		if result.Allowed == 0 {
+			status.Code = OverLimit
+			resp.OverallCode = OverLimit
+		}
+		resp.Statuses[i] = status
+	}
+
+	return resp, nil
+}
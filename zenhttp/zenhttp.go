@@ -0,0 +1,159 @@
+// Package zenhttp provides net/http middleware for a zenlimiter.Limiter,
+// with per-route Limit overrides and a shared "everything else" bucket for
+// routes that don't match a specific override.
+package zenhttp
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/driftdev/zenlimiter"
+)
+
+const unmatchedKey = "__unmatched__"
+
+// KeyFunc extracts the rate-limiting key for an inbound request.
+type KeyFunc func(r *http.Request) string
+
+// RouteLimit overrides the default Limit for requests whose path starts
+// with Pattern.
+type RouteLimit struct {
+	Pattern string
+	Limit   zenlimiter.Limit
+}
+
+// Options configures Middleware.
+type Options struct {
+	// KeyFunc extracts the client key for a request. Defaults to
+	// KeyByIP(TrustedCIDRs), i.e. RemoteAddr unless it is a trusted proxy.
+	KeyFunc KeyFunc
+	// TrustedCIDRs lists proxy networks allowed to set X-Forwarded-For.
+	// Requests from any other RemoteAddr have X-Forwarded-For ignored.
+	TrustedCIDRs []netip.Prefix
+	// DefaultLimit is applied to routes with no matching RouteLimit.
+	DefaultLimit zenlimiter.Limit
+	// Routes lists per-route Limit overrides, matched by longest Pattern prefix.
+	Routes []RouteLimit
+	// ShareUnmatchedBucket, when true, makes every unmatched route draw
+	// from one shared bucket (keyed independently of the client) enforced
+	// at DefaultLimit.Rate * UnmatchedMultiplier, instead of giving each
+	// client their own bucket under DefaultLimit.
+	ShareUnmatchedBucket bool
+	// UnmatchedMultiplier scales DefaultLimit's rate and burst for the
+	// shared unmatched-route bucket. Defaults to 1 if unset.
+	UnmatchedMultiplier int
+}
+
+// Middleware returns an http.Handler wrapper that enforces limiter against
+// every request, selecting the Limit via Options.Routes (falling back to
+// Options.DefaultLimit), and writes the standard RateLimit-Limit,
+// RateLimit-Remaining, and RateLimit-Reset headers. Denied requests get
+// Retry-After and a 429 Too Many Requests status.
+func Middleware(limiter *zenlimiter.Limiter, opts Options) func(http.Handler) http.Handler {
+	keyFunc := opts.KeyFunc
+	if keyFunc == nil {
+		keyFunc = KeyByIP(opts.TrustedCIDRs)
+	}
+
+	multiplier := opts.UnmatchedMultiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	unmatchedLimit := opts.DefaultLimit
+	unmatchedLimit.Rate *= multiplier
+	unmatchedLimit.Burst *= multiplier
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit, matched := routeLimit(opts.Routes, r.URL.Path)
+
+			key := keyFunc(r)
+			if !matched {
+				limit = opts.DefaultLimit
+				if opts.ShareUnmatchedBucket {
+					key = unmatchedKey
+					limit = unmatchedLimit
+				}
+			}
+
+			result, err := limiter.Allow(r.Context(), key, limit)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			setHeaders(w, result)
+			if result.Allowed == 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func routeLimit(routes []RouteLimit, path string) (zenlimiter.Limit, bool) {
+	var best RouteLimit
+	matched := false
+	for _, route := range routes {
+		if !strings.HasPrefix(path, route.Pattern) {
+			continue
+		}
+		if !matched || len(route.Pattern) > len(best.Pattern) {
+			best = route
+			matched = true
+		}
+	}
+	return best.Limit, matched
+}
+
+func setHeaders(w http.ResponseWriter, result *zenlimiter.Result) {
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit.Rate))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(int(result.ResetAfter.Seconds())))
+}
+
+// KeyByIP returns a KeyFunc that honors X-Forwarded-For only when
+// r.RemoteAddr falls within one of trustedCIDRs, falling back to
+// RemoteAddr otherwise. This prevents untrusted clients from spoofing their
+// rate-limit key via the forwarding header.
+func KeyByIP(trustedCIDRs []netip.Prefix) KeyFunc {
+	return func(r *http.Request) string {
+		remoteHost, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			remoteHost = r.RemoteAddr
+		}
+
+		if isTrustedProxy(remoteHost, trustedCIDRs) {
+			if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+				if idx := strings.IndexByte(fwd, ','); idx >= 0 {
+					return strings.TrimSpace(fwd[:idx])
+				}
+				return strings.TrimSpace(fwd)
+			}
+		}
+
+		return remoteHost
+	}
+}
+
+func isTrustedProxy(host string, trustedCIDRs []netip.Prefix) bool {
+	if len(trustedCIDRs) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	for _, cidr := range trustedCIDRs {
+		if cidr.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+// Package zenhttplimit provides net/http middleware that enforces a
+// zenlimiter.LimiterProvider rate limit and reports it via the IETF draft
+// RateLimit-* response headers. Because it is built on LimiterProvider
+// rather than a concrete client, it works unmodified against any backend
+// (zlredis, zlechovault, zlmemory, ...).
+package zenhttplimit
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/driftdev/zenlimiter"
+)
+
+// KeyFunc extracts the rate-limiting key for an inbound request.
+type KeyFunc func(r *http.Request) string
+
+// Exemptions lets callers skip rate limiting entirely for trusted traffic.
+type Exemptions struct {
+	Origins    []string
+	UserAgents []string
+	CIDRs      []netip.Prefix
+}
+
+func (e Exemptions) isExempt(r *http.Request, ip string) bool {
+	for _, origin := range e.Origins {
+		if r.Header.Get("Origin") == origin {
+			return true
+		}
+	}
+	for _, ua := range e.UserAgents {
+		if r.Header.Get("User-Agent") == ua {
+			return true
+		}
+	}
+	if ip == "" || len(e.CIDRs) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	for _, cidr := range e.CIDRs {
+		if cidr.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware returns an http.Handler wrapper that calls limiter.Allow for
+// every request using the key returned by keyFunc, writing the standard
+// RateLimit-Limit, RateLimit-Remaining, and RateLimit-Reset headers. Denied
+// requests additionally get Retry-After and a 429 Too Many Requests status.
+// trustedCIDRs is used only to resolve the request's IP for
+// exemptions.CIDRs matching; it has no effect on keyFunc itself (pass
+// KeyByIP(trustedCIDRs) as keyFunc to also key rate limiting by the
+// trusted-proxy-aware IP).
+func Middleware(limiter *zenlimiter.Limiter, keyFunc KeyFunc, limit zenlimiter.Limit, trustedCIDRs []netip.Prefix, exemptions Exemptions) func(http.Handler) http.Handler {
+	ipFunc := KeyByIP(trustedCIDRs)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ipFunc(r)
+			if exemptions.isExempt(r, ip) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := limiter.Allow(r.Context(), keyFunc(r), limit)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			setHeaders(w, result)
+			if result.Allowed == 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func setHeaders(w http.ResponseWriter, result *zenlimiter.Result) {
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit.Rate))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(int(result.ResetAfter.Seconds())))
+}
+
+// KeyByIP returns a KeyFunc that honors X-Forwarded-For and X-Real-IP only
+// when r.RemoteAddr falls within one of trustedCIDRs, falling back to
+// RemoteAddr otherwise. This prevents untrusted clients from spoofing their
+// rate-limit key via the forwarding headers.
+func KeyByIP(trustedCIDRs []netip.Prefix) KeyFunc {
+	return func(r *http.Request) string {
+		remoteHost, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			remoteHost = r.RemoteAddr
+		}
+
+		if isTrustedProxy(remoteHost, trustedCIDRs) {
+			if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+				if idx := strings.IndexByte(fwd, ','); idx >= 0 {
+					return strings.TrimSpace(fwd[:idx])
+				}
+				return strings.TrimSpace(fwd)
+			}
+			if real := r.Header.Get("X-Real-IP"); real != "" {
+				return real
+			}
+		}
+
+		return remoteHost
+	}
+}
+
+func isTrustedProxy(host string, trustedCIDRs []netip.Prefix) bool {
+	if len(trustedCIDRs) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	for _, cidr := range trustedCIDRs {
+		if cidr.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,8 @@ type LimiterProvider interface {
 	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
 	AllowN(ctx context.Context, key string, limit Limit, n int) (*Result, error)
 	AllowAtMost(ctx context.Context, key string, limit Limit, n int) (*Result, error)
+	AllowMany(ctx context.Context, key string, limits []Limit) ([]*Result, error)
+	AllowManyN(ctx context.Context, key string, limits []Limit, n int) ([]*Result, error)
 	Reset(ctx context.Context, key string) error
 }
 
@@ -31,6 +33,30 @@ func (l *Limiter) AllowAtMost(ctx context.Context, key string, limit Limit, n in
 	return l.limiter.AllowAtMost(ctx, key, limit, n)
 }
 
+func (l *Limiter) AllowMany(ctx context.Context, key string, limits []Limit) ([]*Result, error) {
+	return l.limiter.AllowMany(ctx, key, limits)
+}
+
+func (l *Limiter) AllowManyN(ctx context.Context, key string, limits []Limit, n int) ([]*Result, error) {
+	return l.limiter.AllowManyN(ctx, key, limits, n)
+}
+
 func (l *Limiter) Reset(ctx context.Context, key string) error {
 	return l.limiter.Reset(ctx, key)
 }
+
+// LimitFn resolves the effective Limit for a key at call time, instead of
+// requiring callers to pass a fixed Limit to every Allow call. This enables
+// tiered plans, per-tenant overrides loaded from a config store, or
+// time-of-day limits without threading a Limit through every call site.
+type LimitFn func(ctx context.Context, key string) (Limit, error)
+
+// AllowFn resolves the Limit for key via fn and then allows a single
+// request against it.
+func (l *Limiter) AllowFn(ctx context.Context, key string, fn LimitFn) (*Result, error) {
+	limit, err := fn(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return l.Allow(ctx, key, limit)
+}
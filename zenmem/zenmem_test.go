@@ -0,0 +1,91 @@
+package zenmem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/driftdev/zenlimiter"
+)
+
+func TestAllowNConsumesBurstThenDenies(t *testing.T) {
+	b := NewBackend(time.Hour)
+	defer b.Close()
+	ctx := context.Background()
+	limit := zenlimiter.Limit{Rate: 1, Burst: 2, Period: time.Second}
+
+	for i := 0; i < 2; i++ {
+		res, err := b.AllowN(ctx, "k", limit, 1)
+		if err != nil {
+			t.Fatalf("AllowN #%d: %v", i, err)
+		}
+		if res.Allowed != 1 {
+			t.Fatalf("AllowN #%d: Allowed = %d, want 1", i, res.Allowed)
+		}
+	}
+
+	res, err := b.AllowN(ctx, "k", limit, 1)
+	if err != nil {
+		t.Fatalf("AllowN over burst: %v", err)
+	}
+	if res.Allowed != 0 || res.Remaining != 0 {
+		t.Fatalf("AllowN over burst = Allowed=%d Remaining=%d, want 0 0", res.Allowed, res.Remaining)
+	}
+}
+
+// TestAllowManyNDeniesWithoutPartialCommit guards against the partial-commit
+// bug where a denial from one tier still advanced the TAT of tiers that
+// individually had headroom, letting a caller exceed those tiers over
+// repeated denied calls.
+func TestAllowManyNDeniesWithoutPartialCommit(t *testing.T) {
+	b := NewBackend(time.Hour)
+	defer b.Close()
+	ctx := context.Background()
+
+	roomy := zenlimiter.Limit{Rate: 1000, Burst: 1000, Period: time.Second}
+	tight := zenlimiter.Limit{Rate: 1, Burst: 1, Period: time.Hour}
+	limits := []zenlimiter.Limit{roomy, tight}
+
+	if _, err := b.AllowManyN(ctx, "k", limits, 1); err != nil {
+		t.Fatalf("first AllowManyN: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		results, err := b.AllowManyN(ctx, "k", limits, 1)
+		if err != nil {
+			t.Fatalf("AllowManyN #%d: %v", i, err)
+		}
+		if results[0].Allowed != 0 || results[1].Allowed != 0 {
+			t.Fatalf("AllowManyN #%d: Allowed = [%d %d], want [0 0] (overall denial must zero every tier's Allowed)", i, results[0].Allowed, results[1].Allowed)
+		}
+		if results[0].Remaining <= 0 {
+			t.Fatalf("AllowManyN #%d: roomy tier Remaining = %d, want > 0 (its state must not have been consumed by the denied request)", i, results[0].Remaining)
+		}
+	}
+}
+
+func TestResetClearsState(t *testing.T) {
+	b := NewBackend(time.Hour)
+	defer b.Close()
+	ctx := context.Background()
+	limit := zenlimiter.Limit{Rate: 1, Burst: 1, Period: time.Hour}
+
+	if _, err := b.AllowN(ctx, "k", limit, 1); err != nil {
+		t.Fatalf("AllowN: %v", err)
+	}
+	if res, _ := b.AllowN(ctx, "k", limit, 1); res.Allowed != 0 {
+		t.Fatalf("expected burst to be exhausted before Reset")
+	}
+
+	if err := b.Reset(ctx, "k"); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+
+	res, err := b.AllowN(ctx, "k", limit, 1)
+	if err != nil {
+		t.Fatalf("AllowN after Reset: %v", err)
+	}
+	if res.Allowed != 1 {
+		t.Fatalf("AllowN after Reset: Allowed = %d, want 1", res.Allowed)
+	}
+}